@@ -6,12 +6,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"mime/multipart"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 )
@@ -25,6 +25,33 @@ type SABConfigResponse struct {
 	} `json:"config"`
 }
 
+// UploadResult holds the CrowdNFO response to an upload request
+type UploadResult struct {
+	StatusCode int
+	Body       string
+}
+
+// UploadError describes a CrowdNFO upload request that did not succeed
+type UploadError struct {
+	StatusCode int // 0 if no response was received
+	Message    string
+}
+
+func (e *UploadError) Error() string {
+	return e.Message
+}
+
+// isRetryableUploadError reports whether a failed upload may succeed when sent again later
+// Network errors, rate limiting and server errors are retryable, client errors are not
+func isRetryableUploadError(err error) bool {
+	uploadErr, ok := err.(*UploadError)
+	if !ok {
+		return false
+	}
+
+	return uploadErr.StatusCode == 0 || uploadErr.StatusCode == http.StatusTooManyRequests || uploadErr.StatusCode >= 500
+}
+
 // createHTTPClient creates an HTTP client with TLS configuration based on the verify_ssl setting
 func createHTTPClient(config *Config, timeout time.Duration) *http.Client {
 	client := &http.Client{Timeout: timeout}
@@ -39,24 +66,70 @@ func createHTTPClient(config *Config, timeout time.Duration) *http.Client {
 	return client
 }
 
-func uploadToCrowdNFO(config *Config, releaseName, sabnzbdCategory, hash, finalDir string, mediaInfoJSON []byte, nfoFile, archiveDir string) error {
+func uploadToCrowdNFO(log *Logger, config *Config, releaseName string, qbtArgs QBittorrentArgs, hash, finalDir string, mediaInfoJSON []byte, nfoFile, archiveDir string, releaseReport *ReleaseReport) error {
+	// Map qBittorrent category, tags and tracker to CrowdNFO category
+	resolution := resolveCategory(log, config, qbtArgs, releaseName, listReleaseFiles(finalDir), mediaInfoJSON)
+
+	// Create file list for the whole release
+	fileListEntries, fileListErr := createFileList(finalDir, releaseName)
+
+	return uploadReleaseData(log, config, releaseName, resolution, hash, mediaInfoJSON, nfoFile, fileListEntries, fileListErr, archiveDir, releaseReport)
+}
+
+func uploadEpisodeToCrowdNFO(log *Logger, config *Config, episodeInfo EpisodeInfo, qbtArgs QBittorrentArgs, hash string, mediaInfoJSON []byte, archiveDir string, releaseReport *ReleaseReport) error {
+	// Map qBittorrent category, tags and tracker to CrowdNFO category
+	resolution := resolveCategory(log, config, qbtArgs, episodeInfo.ReleaseName, []string{episodeInfo.VideoFile.Path}, mediaInfoJSON)
+
+	// Create file list for this episode only
+	fileListEntries, fileListErr := createEpisodeFileList(log, episodeInfo)
+
+	return uploadReleaseData(log, config, episodeInfo.ReleaseName, resolution, hash, mediaInfoJSON, episodeInfo.NFOFile, fileListEntries, fileListErr, archiveDir, releaseReport)
+}
+
+// uploadReleaseData uploads MediaInfo, NFO and file list of a single release to all uploaders and records the results in the report
+func uploadReleaseData(log *Logger, config *Config, releaseName string, resolution CategoryResolution, hash string, mediaInfoJSON []byte, nfoFile string, fileListEntries []FileListEntry, fileListErr error, archiveDir string, releaseReport *ReleaseReport) error {
 	var uploadErrors []string
 	var successCount int
 
-	// Map SABnzbd category to CrowdNFO category
-	crowdNFOCategory := mapCategory(config, sabnzbdCategory, releaseName)
+	crowdNFOCategory := resolution.Category
+	if releaseReport != nil {
+		releaseReport.Category = resolution
+		releaseReport.NFOFile = nfoFile
+		if fileListEntries != nil {
+			releaseReport.FileList = fileListEntries
+		}
+	}
+
+	// Every upload goes to all configured targets, a failing target doesn't affect the others
+	uploaders := newUploaders(config)
+	send := func(item QueuedUpload) {
+		for _, uploader := range uploaders {
+			item.Uploader = uploaderTarget(uploader)
+			if err := sendUpload(log, config, uploader, item, releaseReport); err != nil {
+				label := item.historyFileType()
+				if item.Uploader != "" {
+					label += " (" + item.Uploader + ")"
+				}
+				uploadErrors = append(uploadErrors, fmt.Sprintf("%s: %v", label, err))
+			} else {
+				successCount++
+			}
+		}
+	}
 
 	// Upload MediaInfo only if available
 	if mediaInfoJSON != nil && len(mediaInfoJSON) > 0 {
-		if err := uploadFile(config, releaseName, "MediaInfo", "", mediaInfoJSON, hash, crowdNFOCategory, archiveDir); err != nil {
-			uploadErrors = append(uploadErrors, fmt.Sprintf("MediaInfo: %v", err))
-			log.Printf("❌ MediaInfo upload failed: %v", err)
-		} else {
-			log.Printf("✅ MediaInfo uploaded successfully")
-			successCount++
-		}
+		send(QueuedUpload{
+			Kind:        "file",
+			ReleaseName: releaseName,
+			Category:    crowdNFOCategory,
+			Hash:        hash,
+			FileType:    "MediaInfo",
+			Data:        mediaInfoJSON,
+			ArchiveDir:  archiveDir,
+		})
 	} else {
-		log.Printf("⏭️ Skipping MediaInfo upload - no MediaInfo data available")
+		log.Infof("⏭️ Skipping MediaInfo upload - no MediaInfo data available")
 	}
 
 	// Upload NFO if found (independent of MediaInfo upload result)
@@ -64,42 +137,43 @@ func uploadToCrowdNFO(config *Config, releaseName, sabnzbdCategory, hash, finalD
 		nfoData, err := os.ReadFile(nfoFile)
 		if err != nil {
 			uploadErrors = append(uploadErrors, fmt.Sprintf("NFO: failed to read file - %v", err))
-			log.Printf("❌ NFO upload failed: failed to read file - %v", err)
+			log.Errorf("❌ NFO upload failed: failed to read file - %v", err)
+			releaseReport.addUpload("", "NFO", UploadResult{}, fmt.Errorf("failed to read file - %v", err), false)
 		} else {
-			nfoFileName := filepath.Base(nfoFile)
-			if err := uploadFile(config, releaseName, "NFO", nfoFileName, nfoData, hash, crowdNFOCategory, archiveDir); err != nil {
-				uploadErrors = append(uploadErrors, fmt.Sprintf("NFO: %v", err))
-				log.Printf("❌ NFO upload failed: %v", err)
-			} else {
-				log.Printf("✅ NFO uploaded successfully")
-				successCount++
-			}
+			send(QueuedUpload{
+				Kind:             "file",
+				ReleaseName:      releaseName,
+				Category:         crowdNFOCategory,
+				Hash:             hash,
+				FileType:         "NFO",
+				OriginalFileName: filepath.Base(nfoFile),
+				Data:             nfoData,
+				ArchiveDir:       archiveDir,
+			})
 		}
 	} else {
-		log.Printf("⏭️ No NFO file found to upload")
+		log.Infof("⏭️ No NFO file found to upload")
 	}
 
-	// Create and upload file list
-	fileListEntries, err := createFileList(finalDir, releaseName)
-	if err != nil {
-		uploadErrors = append(uploadErrors, fmt.Sprintf("FileList: failed to create file list - %v", err))
-		log.Printf("❌ File list creation failed: %v", err)
+	// Upload file list
+	if fileListErr != nil {
+		uploadErrors = append(uploadErrors, fmt.Sprintf("FileList: failed to create file list - %v", fileListErr))
+		log.Errorf("❌ File list creation failed: %v", fileListErr)
+		releaseReport.addUpload("", "FileList", UploadResult{}, fmt.Errorf("failed to create file list - %v", fileListErr), false)
 	} else if len(fileListEntries) > 0 {
-		fileListRequest := FileListRequest{
+		send(QueuedUpload{
+			Kind:        "filelist",
 			ReleaseName: releaseName,
 			Category:    crowdNFOCategory,
-			Entries:     fileListEntries,
-		}
-
-		if err := uploadFileList(config, fileListRequest); err != nil {
-			uploadErrors = append(uploadErrors, fmt.Sprintf("FileList: %v", err))
-			log.Printf("❌ File list upload failed: %v", err)
-		} else {
-			log.Printf("✅ File list uploaded successfully (%d files)", len(fileListEntries))
-			successCount++
-		}
+			Hash:        hash,
+			FileList: &FileListRequest{
+				ReleaseName: releaseName,
+				Category:    crowdNFOCategory,
+				Entries:     fileListEntries,
+			},
+		})
 	} else {
-		log.Printf("⏭️ No files found for file list")
+		log.Infof("⏭️ No files found for file list")
 	}
 
 	// Return combined errors if any occurred
@@ -114,82 +188,50 @@ func uploadToCrowdNFO(config *Config, releaseName, sabnzbdCategory, hash, finalD
 	return nil
 }
 
-func uploadEpisodeToCrowdNFO(config *Config, episodeInfo EpisodeInfo, sabnzbdCategory, hash string, mediaInfoJSON []byte, archiveDir string) error {
-	var uploadErrors []string
-	var successCount int
-
-	// Map SABnzbd category to CrowdNFO category
-	crowdNFOCategory := mapCategory(config, sabnzbdCategory, episodeInfo.ReleaseName)
-
-	// Upload MediaInfo only if available
-	if mediaInfoJSON != nil && len(mediaInfoJSON) > 0 {
-		if err := uploadFile(config, episodeInfo.ReleaseName, "MediaInfo", "", mediaInfoJSON, hash, crowdNFOCategory, archiveDir); err != nil {
-			uploadErrors = append(uploadErrors, fmt.Sprintf("MediaInfo: %v", err))
-			log.Printf("❌ MediaInfo upload failed: %v", err)
-		} else {
-			log.Printf("✅ MediaInfo uploaded successfully")
-			successCount++
-		}
-	} else {
-		log.Printf("⏭️ Skipping MediaInfo upload - no MediaInfo data available")
+// sendUpload sends an NFO, MediaInfo or file list to one uploader unless the history already has it
+// Failed uploads are queued for a retry if possible, the outcome is recorded in history and report
+func sendUpload(log *Logger, config *Config, uploader Uploader, item QueuedUpload, releaseReport *ReleaseReport) error {
+	fileType := item.historyFileType()
+	label := fileType
+	if item.Kind == "filelist" {
+		label = "File list"
 	}
-
-	// Upload NFO if found (independent of MediaInfo upload result)
-	if episodeInfo.NFOFile != "" {
-		nfoData, err := os.ReadFile(episodeInfo.NFOFile)
-		if err != nil {
-			uploadErrors = append(uploadErrors, fmt.Sprintf("NFO: failed to read file - %v", err))
-			log.Printf("❌ NFO upload failed: failed to read file - %v", err)
-		} else {
-			nfoFileName := filepath.Base(episodeInfo.NFOFile)
-			if err := uploadFile(config, episodeInfo.ReleaseName, "NFO", nfoFileName, nfoData, hash, crowdNFOCategory, archiveDir); err != nil {
-				uploadErrors = append(uploadErrors, fmt.Sprintf("NFO: %v", err))
-				log.Printf("❌ NFO upload failed: %v", err)
-			} else {
-				log.Printf("✅ NFO uploaded successfully")
-				successCount++
-			}
-		}
-	} else {
-		log.Printf("⏭️ No NFO file found to upload")
+	to := ""
+	if item.Uploader != "" {
+		to = " to " + item.Uploader
 	}
 
-	// Create and upload episode file list
-	fileListEntries, err := createEpisodeFileList(episodeInfo)
-	if err != nil {
-		uploadErrors = append(uploadErrors, fmt.Sprintf("FileList: failed to create file list - %v", err))
-		log.Printf("❌ File list creation failed: %v", err)
-	} else if len(fileListEntries) > 0 {
-		fileListRequest := FileListRequest{
-			ReleaseName: episodeInfo.ReleaseName,
-			Category:    crowdNFOCategory,
-			Entries:     fileListEntries,
-		}
-
-		if err := uploadFileList(config, fileListRequest); err != nil {
-			uploadErrors = append(uploadErrors, fmt.Sprintf("FileList: %v", err))
-			log.Printf("❌ File list upload failed: %v", err)
-		} else {
-			log.Printf("✅ File list uploaded successfully (%d files)", len(fileListEntries))
-			successCount++
-		}
-	} else {
-		log.Printf("⏭️ No files found for file list")
+	if isAlreadyUploaded(log, item.Uploader, item.ReleaseName, fileType, item.Hash) {
+		log.Infof("⏭️ %s already uploaded%s according to history, skipping", label, to)
+		releaseReport.addSkippedUpload(item.Uploader, fileType, "history", UploadResult{})
+		return nil
 	}
 
-	// Return combined errors if any occurred
-	if len(uploadErrors) > 0 {
-		if successCount > 0 {
-			return fmt.Errorf("partial_failure:%d successful, %d failed", successCount, len(uploadErrors))
-		} else {
-			return fmt.Errorf("total_failure:%d upload(s) failed", len(uploadErrors))
-		}
+	result, err := item.sendTo(log, uploader)
+	switch {
+	case err != nil && uploader.IsDuplicate(err):
+		log.Infof("⏭️ %s was already submitted to %s", label, item.targetName())
+		recordUpload(log, config, item.Uploader, item.ReleaseName, fileType, item.Hash)
+		releaseReport.addSkippedUpload(item.Uploader, fileType, "duplicate", result)
+		return nil
+	case err != nil:
+		log.Errorf("❌ %s upload%s failed: %v", label, to, err)
+		queued := enqueueUpload(log, config, item, err)
+		releaseReport.addUpload(item.Uploader, fileType, result, err, queued)
+		return err
 	}
 
+	if item.Kind == "filelist" {
+		log.Infof("✅ File list uploaded successfully%s (%d files)", to, len(item.FileList.Entries))
+	} else {
+		log.Infof("✅ %s uploaded successfully%s", label, to)
+	}
+	recordUpload(log, config, item.Uploader, item.ReleaseName, fileType, item.Hash)
+	releaseReport.addUpload(item.Uploader, fileType, result, nil, false)
 	return nil
 }
 
-func uploadFile(config *Config, releaseName, fileType, originalFileName string, fileData []byte, hash, category, archiveDir string) error {
+func uploadFile(log *Logger, config *Config, releaseName, fileType, originalFileName string, fileData []byte, hash, category, archiveDir string) (UploadResult, error) {
 	url := fmt.Sprintf("%s/%s/files", config.BaseURL, releaseName)
 
 	// Create multipart form
@@ -197,21 +239,24 @@ func uploadFile(config *Config, releaseName, fileType, originalFileName string,
 	writer := multipart.NewWriter(&b)
 
 	// Add form fields
-	writer.WriteField("FileType", fileType)
+	fields := [][2]string{{"FileType", fileType}}
 	if originalFileName != "" {
-		writer.WriteField("OriginalFileName", originalFileName)
+		fields = append(fields, [2]string{"OriginalFileName", originalFileName})
 	}
 	if category != "" {
-		writer.WriteField("Category", category)
+		fields = append(fields, [2]string{"Category", category})
 	}
 	if hash != "" {
-		writer.WriteField("FileHash", hash)
+		fields = append(fields, [2]string{"FileHash", hash})
+	}
+	for _, field := range fields {
+		writer.WriteField(field[0], field[1])
 	}
 
 	// Add file
 	part, err := writer.CreateFormFile("File", getFileName(fileType, releaseName, originalFileName))
 	if err != nil {
-		return err
+		return UploadResult{}, err
 	}
 	part.Write(fileData)
 
@@ -220,77 +265,83 @@ func uploadFile(config *Config, releaseName, fileType, originalFileName string,
 	// Create request
 	req, err := http.NewRequest("POST", url, &b)
 	if err != nil {
-		return err
+		return UploadResult{}, err
 	}
 
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 	req.Header.Set("X-Api-Key", config.APIKey)
 	req.Header.Set("User-Agent", getUserAgent())
 
-	// Debug: Print request details
-	//log.Printf("🔍 DEBUG: HTTP Request Details")
-	//log.Printf("   Method: %s", req.Method)
-	//log.Printf("   URL: %s", req.URL.String())
-	//log.Printf("   Headers:")
-	//for name, values := range req.Header {
-	//	for _, value := range values {
-	//		// Mask API key for security
-	//		if name == "X-Api-Key" && len(value) > 8 {
-	//			maskedValue := value[:4] + "****" + value[len(value)-4:]
-	//			log.Printf("     %s: %s", name, maskedValue)
-	//		} else {
-	//			log.Printf("     %s: %s", name, value)
-	//		}
-	//	}
-	//}
-	//log.Printf("   Content-Length: %d bytes", req.ContentLength)
-	//if req.ContentLength == 0 && b.Len() > 0 {
-	//	log.Printf("   Body Size: %d bytes", b.Len())
-	//}
+	// In dry-run mode only print what would be sent, nothing is uploaded or archived
+	if config.DryRun {
+		printDryRunRequest(log, req)
+		log.Infof("   Multipart fields:")
+		for _, field := range fields {
+			log.Infof("     %s: %s", field[0], field[1])
+		}
+		log.Infof("     File: %s (%d bytes)", getFileName(fileType, releaseName, originalFileName), len(fileData))
+		return UploadResult{}, nil
+	}
 
 	// Send request
 	client := createHTTPClient(config, 30*time.Second)
 	resp, err := client.Do(req)
 	if err != nil {
-		return err
+		return UploadResult{}, &UploadError{Message: err.Error()}
 	}
 	defer resp.Body.Close()
 
-	// Debug: Print response details
-	//log.Printf("🔍 DEBUG: HTTP Response Details")
-	//log.Printf("   Status: %s (%d)", resp.Status, resp.StatusCode)
-	//log.Printf("   Headers:")
-	//for name, values := range resp.Header {
-	//	for _, value := range values {
-	//		log.Printf("     %s: %s", name, value)
-	//	}
-	//}
-
 	// Check for update headers
 	checkUpdateHeaders(resp.Header)
 
 	// Read response body for error details
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read response body: %v", err)
+		return UploadResult{StatusCode: resp.StatusCode}, fmt.Errorf("failed to read response body: %v", err)
 	}
 
-	//log.Printf("   Body Size: %d bytes", len(body))
-	//if len(body) > 0 && len(body) < 1000 { // Only log small response bodies
-	//	log.Printf("   Body: %s", string(body))
-	//}
-
+	result := UploadResult{StatusCode: resp.StatusCode, Body: string(body)}
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return fmt.Errorf("upload failed with status %d: %s", resp.StatusCode, string(body))
+		return result, &UploadError{StatusCode: resp.StatusCode, Message: fmt.Sprintf("upload failed with status %d: %s", resp.StatusCode, string(body))}
 	}
 
 	// Archive the uploaded file
 	archiveFile := filepath.Join(archiveDir, getFileName(fileType, releaseName, originalFileName))
 	if err := os.WriteFile(archiveFile, fileData, 0644); err != nil {
-		log.Printf("⚠️ Failed to archive %s: %v", fileType, err)
+		log.Warnf("⚠️ Failed to archive %s: %v", fileType, err)
 	}
 
-	return nil
+	return result, nil
+}
+
+// printDryRunRequest prints method, URL and headers of a request that is not sent in dry-run mode
+// The API key, Authorization and any additional secretHeaders (e.g. configured webhook headers) are masked
+func printDryRunRequest(log *Logger, req *http.Request, secretHeaders ...string) {
+	masked := map[string]bool{"X-Api-Key": true, "Authorization": true}
+	for _, name := range secretHeaders {
+		masked[http.CanonicalHeaderKey(name)] = true
+	}
+
+	log.Infof("🧪 DRY RUN: %s %s", req.Method, req.URL.String())
+	log.Infof("   Headers:")
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range req.Header[name] {
+			// Mask credentials for security
+			if masked[name] {
+				if len(value) > 8 {
+					value = value[:4] + "****" + value[len(value)-4:]
+				} else {
+					value = "****"
+				}
+			}
+			log.Infof("     %s: %s", name, value)
+		}
+	}
 }
 
 func getFileName(fileType, releaseName, originalFileName string) string {
@@ -303,7 +354,7 @@ func getFileName(fileType, releaseName, originalFileName string) string {
 // checkSABnzbdConfig checks if deobfuscate_final_filenames is set to false
 func checkSABnzbdConfig(sabApiUrl, sabApiKey string) error {
 	if sabApiUrl == "" || sabApiKey == "" {
-		log.Println("⚠️ SABnzbd API URL or API Key not provided, skipping deobfuscate check")
+		logWarnf("⚠️ SABnzbd API URL or API Key not provided, skipping deobfuscate check")
 		return nil
 	}
 
@@ -331,26 +382,26 @@ func checkSABnzbdConfig(sabApiUrl, sabApiKey string) error {
 
 	resp, err := client.Get(apiUrl)
 	if err != nil {
-		log.Printf("⚠️ Failed to connect to SABnzbd API, skipping deobfuscate check: %v", err)
+		logWarnf("⚠️ Failed to connect to SABnzbd API, skipping deobfuscate check: %v", err)
 		return nil
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		log.Printf("⚠️ SABnzbd API returned status %d, skipping deobfuscate check", resp.StatusCode)
+		logWarnf("⚠️ SABnzbd API returned status %d, skipping deobfuscate check", resp.StatusCode)
 		return nil
 	}
 
 	// Parse response
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Printf("⚠️ Failed to read SABnzbd API response, skipping deobfuscate check: %v", err)
+		logWarnf("⚠️ Failed to read SABnzbd API response, skipping deobfuscate check: %v", err)
 		return nil
 	}
 
 	var response SABConfigResponse
 	if err := json.Unmarshal(body, &response); err != nil {
-		log.Printf("⚠️ Failed to parse SABnzbd API response, skipping deobfuscate check: %v", err)
+		logWarnf("⚠️ Failed to parse SABnzbd API response, skipping deobfuscate check: %v", err)
 		return nil
 	}
 
@@ -363,75 +414,59 @@ func checkSABnzbdConfig(sabApiUrl, sabApiKey string) error {
 }
 
 // uploadFileList uploads a file list to CrowdNFO
-func uploadFileList(config *Config, fileListRequest FileListRequest) error {
+func uploadFileList(log *Logger, config *Config, fileListRequest FileListRequest) (UploadResult, error) {
 	url := fmt.Sprintf("%s/%s/filelists", config.BaseURL, fileListRequest.ReleaseName)
 
 	// Convert to JSON
 	jsonData, err := json.Marshal(fileListRequest)
 	if err != nil {
-		return fmt.Errorf("failed to marshal file list: %v", err)
+		return UploadResult{}, fmt.Errorf("failed to marshal file list: %v", err)
 	}
 
 	// Create request
 	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return err
+		return UploadResult{}, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-Api-Key", config.APIKey)
 	req.Header.Set("User-Agent", getUserAgent())
 
-	//// Debug: Print request details
-	//log.Printf("🔍 DEBUG: File List HTTP Request Details")
-	//log.Printf("   Method: %s", req.Method)
-	//log.Printf("   URL: %s", req.URL.String())
-	//log.Printf("   Headers:")
-	//for name, values := range req.Header {
-	//	for _, value := range values {
-	//		// Mask API key for security
-	//		if name == "X-Api-Key" && len(value) > 8 {
-	//			maskedValue := value[:4] + "****" + value[len(value)-4:]
-	//			log.Printf("     %s: %s", name, maskedValue)
-	//		} else {
-	//			log.Printf("     %s: %s", name, value)
-	//		}
-	//	}
-	//}
-	//log.Printf("   Content-Length: %d bytes", len(jsonData))
+	// In dry-run mode only print what would be sent
+	if config.DryRun {
+		printDryRunRequest(log, req)
+		var prettyJSON bytes.Buffer
+		if err := json.Indent(&prettyJSON, jsonData, "   ", "  "); err != nil {
+			prettyJSON.Reset()
+			prettyJSON.Write(jsonData)
+		}
+		log.Infof("   JSON body:\n   %s", prettyJSON.String())
+		return UploadResult{}, nil
+	}
 
 	// Send request
 	client := createHTTPClient(config, 30*time.Second)
 	resp, err := client.Do(req)
 	if err != nil {
-		return err
+		return UploadResult{}, &UploadError{Message: err.Error()}
 	}
 	defer resp.Body.Close()
 
-	// Debug: Print response details
-	//log.Printf("🔍 DEBUG: File List HTTP Response Details")
-	//log.Printf("   Status: %s (%d)", resp.Status, resp.StatusCode)
-	//log.Printf("   Headers:")
-	//for name, values := range resp.Header {
-	//	for _, value := range values {
-	//		log.Printf("     %s: %s", name, value)
-	//	}
-	//}
-	//log.Printf("   Content-Length: %d bytes", resp.ContentLength)
-
 	// Check response
+	body, _ := io.ReadAll(resp.Body)
+	result := UploadResult{StatusCode: resp.StatusCode, Body: string(body)}
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
 		if resp.StatusCode == http.StatusUnauthorized {
-			return fmt.Errorf("unauthorized: please check your API key in config.json")
+			return result, &UploadError{StatusCode: resp.StatusCode, Message: "unauthorized: please check your API key in config.json"}
 		}
 		if resp.StatusCode == http.StatusBadRequest {
-			return fmt.Errorf("%s", string(body))
+			return result, &UploadError{StatusCode: resp.StatusCode, Message: string(body)}
 		}
-		return fmt.Errorf("file list upload failed with status %d: %s", resp.StatusCode, string(body))
+		return result, &UploadError{StatusCode: resp.StatusCode, Message: fmt.Sprintf("file list upload failed with status %d: %s", resp.StatusCode, string(body))}
 	}
 
-	return nil
+	return result, nil
 }
 
 // UmlautadaptarrResponse represents the response from Umlautadaptarr API
@@ -528,6 +563,9 @@ func isRunningInContainer() bool {
 
 // checkUpdateHeaders checks response headers for update notifications
 func checkUpdateHeaders(headers http.Header) {
+	updateMu.Lock()
+	defer updateMu.Unlock()
+
 	// Check for X-Client-Update-Available header
 	if updateAvailableHeader := headers.Get("X-Client-Update-Available"); updateAvailableHeader != "" {
 		if strings.ToLower(updateAvailableHeader) == "true" || updateAvailableHeader == "1" {
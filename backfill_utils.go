@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// BackfillState is the resume checkpoint for backfill runs, keyed by absolute library root
+type BackfillState struct {
+	Roots map[string]*BackfillRootState `json:"roots"`
+}
+
+// BackfillRootState lists the releases of one library root that were already processed
+type BackfillRootState struct {
+	Completed map[string]time.Time `json:"completed"`
+}
+
+// getBackfillStatePath returns the location of the backfill checkpoint next to the config file
+func getBackfillStatePath() string {
+	return filepath.Join(getCurrentDir(), "crowdclient-backfill-state.json")
+}
+
+// backfillCheckpoint guards the checkpoint file while several workers update it
+type backfillCheckpoint struct {
+	mu    sync.Mutex
+	root  string
+	state *BackfillState
+}
+
+// loadBackfillCheckpoint loads the checkpoint for the given root, optionally discarding previous progress
+func loadBackfillCheckpoint(root string, restart bool) (*backfillCheckpoint, error) {
+	state := &BackfillState{}
+	if err := readJSONFile(getBackfillStatePath(), state); err != nil {
+		return nil, fmt.Errorf("failed to read backfill checkpoint: %v", err)
+	}
+	if state.Roots == nil {
+		state.Roots = make(map[string]*BackfillRootState)
+	}
+	if state.Roots[root] == nil || restart {
+		state.Roots[root] = &BackfillRootState{Completed: make(map[string]time.Time)}
+	}
+
+	return &backfillCheckpoint{root: root, state: state}, nil
+}
+
+// isCompleted reports whether a release was processed in an earlier run
+func (c *backfillCheckpoint) isCompleted(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.state.Roots[c.root].Completed[name]
+	return ok
+}
+
+// markCompleted records a processed release and saves the checkpoint immediately
+func (c *backfillCheckpoint) markCompleted(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.state.Roots[c.root].Completed[name] = time.Now()
+	if err := writeJSONFile(getBackfillStatePath(), c.state); err != nil {
+		logWarnf("⚠️ Failed to save backfill checkpoint: %v", err)
+	}
+}
+
+// findBackfillReleases returns all top-level entries of root, sorted by name
+func findBackfillReleases(root string) ([]string, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+
+	releases := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		// Skip hidden files and directories like .DS_Store or .stfolder
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		releases = append(releases, entry.Name())
+	}
+
+	sort.Strings(releases)
+	return releases, nil
+}
+
+// runBackfill processes every top-level entry of an existing library directory as a release
+func runBackfill(args []string) {
+	flags := flag.NewFlagSet("backfill", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "run the pipeline without sending anything to CrowdNFO")
+	concurrency := flags.Int("concurrency", 1, "number of releases processed in parallel")
+	category := flags.String("category", "", "qBittorrent category used for category mapping")
+	restart := flags.Bool("restart", false, "ignore the checkpoint and process all releases again")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s backfill [flags] <dir>\n", filepath.Base(os.Args[0]))
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	root, err := filepath.Abs(flags.Arg(0))
+	if err != nil {
+		logFatalf("❌ Invalid backfill directory: %v", err)
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		logFatalf("❌ Failed to load configuration: %v", err)
+	}
+
+	// Post-processing scripts are meant for freshly finished torrents, not for old releases
+	backfillConfig := *config
+	backfillConfig.PostProcessing = PostProcessingConfig{}
+	if *dryRun {
+		backfillConfig.DryRun = true
+	}
+
+	releases, err := findBackfillReleases(root)
+	if err != nil {
+		logFatalf("❌ Failed to read backfill directory: %v", err)
+	}
+
+	checkpoint, err := loadBackfillCheckpoint(root, *restart)
+	if err != nil {
+		logFatalf("❌ %v", err)
+	}
+
+	pending := make([]string, 0, len(releases))
+	for _, name := range releases {
+		if !checkpoint.isCompleted(name) {
+			pending = append(pending, name)
+		}
+	}
+
+	logInfof("📚 Found %d releases in %s, %d already processed, %d pending", len(releases), root, len(releases)-len(pending), len(pending))
+
+	if *concurrency < 1 {
+		*concurrency = 1
+	}
+
+	// Stop handing out new releases on SIGINT/SIGTERM, running ones are finished first
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < *concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				name := pending[index]
+				logInfof("📦 [%d/%d] %s", index+1, len(pending), name)
+
+				qbtArgs := QBittorrentArgs{
+					TorrentName: name,
+					ContentPath: filepath.Join(root, name),
+					Category:    *category,
+					SavePath:    root,
+				}
+				if info, err := os.Stat(qbtArgs.ContentPath); err == nil && info.IsDir() {
+					qbtArgs.RootPath = qbtArgs.ContentPath
+				}
+				if entries, err := createFileList(qbtArgs.ContentPath, name); err == nil {
+					var totalSize int64
+					for _, entry := range entries {
+						totalSize += entry.FileSizeBytes
+					}
+					qbtArgs.TorrentSize = strconv.FormatInt(totalSize, 10)
+					qbtArgs.NumberFiles = strconv.Itoa(len(entries))
+				}
+
+				if err := processTorrent(context.Background(), &backfillConfig, qbtArgs); err != nil {
+					logWarnf("⚠️ %s not completed: %v", name, err)
+					continue
+				}
+				if !backfillConfig.DryRun {
+					checkpoint.markCompleted(name)
+				}
+			}
+		}()
+	}
+
+	interrupted := false
+dispatch:
+	for index := range pending {
+		select {
+		case <-stop:
+			interrupted = true
+			break dispatch
+		case jobs <- index:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if interrupted {
+		logWarnf("⚠️ Backfill interrupted, run the same command again to resume")
+		return
+	}
+
+	logInfof("✅ Backfill completed: %d releases processed", len(pending))
+}
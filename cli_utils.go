@@ -0,0 +1,154 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// qbtArgField describes one qBittorrent parameter, in the order of the positional arguments
+type qbtArgField struct {
+	Placeholder string
+	Flag        string
+	Description string
+	Value       func(*QBittorrentArgs) *string
+}
+
+var qbtArgFields = []qbtArgField{
+	{"%N", "name", "Torrent name", func(a *QBittorrentArgs) *string { return &a.TorrentName }},
+	{"%F", "content-path", "Content path", func(a *QBittorrentArgs) *string { return &a.ContentPath }},
+	{"%L", "category", "Category", func(a *QBittorrentArgs) *string { return &a.Category }},
+	{"%I", "info-hash", "Info hash v1", func(a *QBittorrentArgs) *string { return &a.InfoHash }},
+	{"%D", "save-path", "Save path", func(a *QBittorrentArgs) *string { return &a.SavePath }},
+	{"%G", "tags", "Tags (comma separated)", func(a *QBittorrentArgs) *string { return &a.Tags }},
+	{"%J", "info-hash-v2", "Info hash v2", func(a *QBittorrentArgs) *string { return &a.InfoHashV2 }},
+	{"%K", "torrent-id", "Torrent ID", func(a *QBittorrentArgs) *string { return &a.TorrentID }},
+	{"%R", "root-path", "Root path", func(a *QBittorrentArgs) *string { return &a.RootPath }},
+	{"%T", "tracker", "Tracker", func(a *QBittorrentArgs) *string { return &a.Tracker }},
+	{"%Z", "torrent-size", "Torrent size in bytes", func(a *QBittorrentArgs) *string { return &a.TorrentSize }},
+	{"%C", "number-files", "Number of files", func(a *QBittorrentArgs) *string { return &a.NumberFiles }},
+}
+
+// printUsage prints the supported invocation forms
+func printUsage(w io.Writer) {
+	name := filepath.Base(os.Args[0])
+	fmt.Fprintf(w, "Usage:\n")
+	fmt.Fprintf(w, "  %s \"%%N\" \"%%F\" [\"%%L\" \"%%I\" \"%%D\" \"%%G\" \"%%J\" \"%%K\" \"%%R\" \"%%T\" \"%%Z\" \"%%C\"]\n", name)
+	fmt.Fprintf(w, "  %s --name \"%%N\" --content-path \"%%F\" [flags]\n", name)
+	fmt.Fprintf(w, "  %s serve [-once] [-skip-existing]\n", name)
+	fmt.Fprintf(w, "  %s backfill [flags] <dir>\n", name)
+	fmt.Fprintf(w, "  %s --retry-queue\n", name)
+	fmt.Fprintf(w, "  %s --prune-hash-cache\n", name)
+	fmt.Fprintf(w, "  %s --version\n", name)
+	fmt.Fprintf(w, "\nFlags:\n")
+	for _, field := range qbtArgFields {
+		fmt.Fprintf(w, "  --%-14s %s (%s)\n", field.Flag, field.Description, field.Placeholder)
+	}
+	fmt.Fprintf(w, "  --%-14s %s\n", "dry-run", "Print API requests instead of sending them")
+}
+
+// parseQBittorrentArgs parses either the named flags or the positional qBittorrent arguments
+// Positional arguments may be cut off after %F, missing ones are left empty
+func parseQBittorrentArgs(args []string) (QBittorrentArgs, error) {
+	var qbtArgs QBittorrentArgs
+
+	if len(args) > 0 && isQBittorrentFlag(args[0]) {
+		flags := flag.NewFlagSet("crowdclient", flag.ContinueOnError)
+		flags.SetOutput(io.Discard)
+		for _, field := range qbtArgFields {
+			flags.StringVar(field.Value(&qbtArgs), field.Flag, "", field.Description)
+		}
+		if err := flags.Parse(args); err != nil {
+			return qbtArgs, err
+		}
+		if flags.NArg() > 0 {
+			return qbtArgs, fmt.Errorf("unexpected positional arguments after flags: %s", strings.Join(flags.Args(), " "))
+		}
+	} else {
+		if len(args) > len(qbtArgFields) {
+			return qbtArgs, fmt.Errorf("too many arguments: expected at most %d, got %d", len(qbtArgFields), len(args))
+		}
+		for i, arg := range args {
+			*qbtArgFields[i].Value(&qbtArgs) = arg
+		}
+	}
+
+	return qbtArgs, nil
+}
+
+// isQBittorrentFlag reports whether arg is one of the named flags, a torrent name like "-=GRP=- Show" is positional
+func isQBittorrentFlag(arg string) bool {
+	if arg == "--" {
+		return true
+	}
+	if !strings.HasPrefix(arg, "-") {
+		return false
+	}
+
+	name := strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+	name, _, _ = strings.Cut(name, "=")
+	for _, field := range qbtArgFields {
+		if name == field.Flag {
+			return true
+		}
+	}
+	return false
+}
+
+// checkContentPath reports whether the content path passed by qBittorrent is accessible from this process
+// It is not part of validateQBittorrentArgs, a missing path must not prevent post-processing in hook mode
+func checkContentPath(qbtArgs QBittorrentArgs) error {
+	if _, err := os.Stat(qbtArgs.ContentPath); err != nil {
+		return fmt.Errorf("content path is not accessible: %v", err)
+	}
+	return nil
+}
+
+// validateQBittorrentArgs checks the mandatory fields and warns about missing fields that configured features rely on
+func validateQBittorrentArgs(config *Config, qbtArgs QBittorrentArgs) error {
+	if qbtArgs.TorrentName == "" {
+		return fmt.Errorf("torrent name is required (--name or \"%%N\" as first argument)")
+	}
+	if qbtArgs.ContentPath == "" {
+		return fmt.Errorf("content path is required (--content-path or \"%%F\" as second argument)")
+	}
+
+	if qbtArgs.TorrentSize != "" {
+		if _, err := strconv.ParseInt(qbtArgs.TorrentSize, 10, 64); err != nil {
+			return fmt.Errorf("invalid torrent size '%s' (%%Z must be a number of bytes)", qbtArgs.TorrentSize)
+		}
+	}
+	if qbtArgs.NumberFiles != "" {
+		if _, err := strconv.Atoi(qbtArgs.NumberFiles); err != nil {
+			return fmt.Errorf("invalid number of files '%s' (%%C must be a number)", qbtArgs.NumberFiles)
+		}
+	}
+
+	if qbtArgs.Category == "" {
+		logInfof("ℹ️ No category passed (%%L), CrowdNFO category will be detected from tags, tracker or the release name")
+	}
+
+	// Post-processing arguments are substituted with empty strings if the placeholder was not passed
+	commands := []PostProcessCommand{config.PostProcessing.Global}
+	for _, cmd := range config.PostProcessing.Categories {
+		commands = append(commands, cmd)
+	}
+	for _, cmd := range commands {
+		if !cmd.Enabled {
+			continue
+		}
+		for _, arg := range cmd.Arguments {
+			for _, field := range qbtArgFields {
+				if strings.Contains(arg, field.Placeholder) && *field.Value(&qbtArgs) == "" {
+					logWarnf("⚠️ Post-processing command '%s' uses %s (%s), but it was not passed (--%s)", cmd.Command, field.Placeholder, field.Description, field.Flag)
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseQBittorrentArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    QBittorrentArgs
+		wantErr bool
+	}{
+		{
+			name: "positional",
+			args: []string{"Show.S01E01.1080p.WEB.h264-GRP", "/downloads/Show.S01E01", "tv"},
+			want: QBittorrentArgs{TorrentName: "Show.S01E01.1080p.WEB.h264-GRP", ContentPath: "/downloads/Show.S01E01", Category: "tv"},
+		},
+		{
+			name: "positional name starting with a dash",
+			args: []string{"-=GRP=- Show.S01E01.1080p", "/downloads/Show"},
+			want: QBittorrentArgs{TorrentName: "-=GRP=- Show.S01E01.1080p", ContentPath: "/downloads/Show"},
+		},
+		{
+			name: "flags",
+			args: []string{"--name", "Show.S01E01", "--content-path", "/downloads/Show", "--tags", "a,b"},
+			want: QBittorrentArgs{TorrentName: "Show.S01E01", ContentPath: "/downloads/Show", Tags: "a,b"},
+		},
+		{
+			name: "single dash flags with values",
+			args: []string{"-name=-=GRP=- Show", "-content-path=/downloads/Show"},
+			want: QBittorrentArgs{TorrentName: "-=GRP=- Show", ContentPath: "/downloads/Show"},
+		},
+		{
+			name:    "unknown flag after known flag",
+			args:    []string{"--name", "Show", "--bogus", "x"},
+			wantErr: true,
+		},
+		{
+			name:    "too many positional arguments",
+			args:    []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12", "13"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseQBittorrentArgs(tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseQBittorrentArgs(%q) expected an error, got %+v", tt.args, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseQBittorrentArgs(%q) returned error: %v", tt.args, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseQBittorrentArgs(%q)\n got  %+v\n want %+v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
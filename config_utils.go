@@ -3,23 +3,41 @@ package main
 import (
 	"encoding/json"
 	"fmt"
-	"log"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 )
 
 type Config struct {
-	APIKey             string               `json:"api_key"`
-	BaseURL            string               `json:"base_url"`
-	MediaInfoPath      string               `json:"mediainfo_path"`
-	MaxHashFileSize    string               `json:"max_hash_file_size"`
-	VerifySSL          bool                 `json:"verify_ssl"`
-	CategoryMappings   map[string][]string  `json:"category_mappings,omitempty"`
-	ExcludedCategories []string             `json:"excluded_categories,omitempty"`
-	PostProcessing     PostProcessingConfig `json:"post_processing"`
-	Umlautadaptarr     UmlautadaptarrConfig `json:"umlautadaptarr"`
+	APIKey             string                 `json:"api_key"`
+	BaseURL            string                 `json:"base_url"`
+	MediaInfoPath      string                 `json:"mediainfo_path"`
+	MaxHashFileSize    string                 `json:"max_hash_file_size"`
+	VerifySSL          bool                   `json:"verify_ssl"`
+	DryRun             bool                   `json:"dry_run"`
+	CategoryMappings   map[string][]string    `json:"category_mappings,omitempty"`
+	CategoryRules      []CategoryRule         `json:"category_rules,omitempty"`
+	TagMappings        map[string][]string    `json:"tag_mappings,omitempty"`
+	TrackerMappings    map[string][]string    `json:"tracker_mappings,omitempty"`
+	ExcludedCategories []string               `json:"excluded_categories,omitempty"`
+	Filters            FiltersConfig          `json:"filters"`
+	PostProcessing     PostProcessingConfig   `json:"post_processing"`
+	Umlautadaptarr     UmlautadaptarrConfig   `json:"umlautadaptarr"`
+	UploadQueue        UploadQueueConfig      `json:"upload_queue"`
+	Uploaders          []UploaderConfig       `json:"uploaders,omitempty"` // Upload targets, CrowdNFO only if empty
+	SeasonPack         SeasonPackConfig       `json:"season_pack"`
+	Hashing            HashingConfig          `json:"hashing"`
+	Verification       VerificationConfig     `json:"verification"`
+	ContentDetection   ContentDetectionConfig `json:"content_detection"`
+	ReleaseNames       ReleaseNameConfig      `json:"release_names"`
+	QBittorrent        QBittorrentConfig      `json:"qbittorrent"`
+	Logging            LoggingConfig          `json:"logging"`
+
+	categoryRules []CategoryRule   // Compiled by loadConfig
+	junkPatterns  []*regexp.Regexp // Compiled by loadConfig
 }
 
 type PostProcessingConfig struct {
@@ -38,21 +56,195 @@ type UmlautadaptarrConfig struct {
 	BaseURL string `json:"base_url"`
 }
 
+type UploadQueueConfig struct {
+	Enabled     bool `json:"enabled"`
+	MaxAttempts int  `json:"max_attempts"`
+}
+
+type SeasonPackConfig struct {
+	HashWorkers        int               `json:"hash_workers"`                  // Episodes hashed in parallel, keep at 1 for spinning disks
+	UploadWorkers      int               `json:"upload_workers"`                // Episodes uploaded in parallel
+	Specials           string            `json:"specials"`                      // "split" uploads specials/OVAs as own releases, "pack" keeps them in the pack
+	Strategy           string            `json:"strategy"`                      // "split" into episodes, upload the whole "pack" as one release, or "both"
+	CategoryStrategies map[string]string `json:"category_strategies,omitempty"` // Strategy per qBittorrent category (%L), overrides strategy
+}
+
+type HashingConfig struct {
+	Algorithms       []string `json:"algorithms"`          // Additional digests computed in the same pass: crc32, xxh64 (sha256 is always calculated)
+	TimeoutSeconds   int      `json:"timeout_seconds"`     // Give up hashing a single file after this time, 0 disables the timeout
+	RateLimitMBps    float64  `json:"rate_limit_mb_per_s"` // Limit disk reads while hashing, 0 disables the limit
+	ProgressInterval int      `json:"progress_interval"`   // Seconds between progress messages
+	Cache            bool     `json:"cache"`               // Reuse digests of unchanged files from crowdclient-hashcache.json
+}
+
+type VerificationConfig struct {
+	Enabled        bool `json:"enabled"`          // Verify releases against included .sfv/.md5/.sha1/.sha256 files
+	BlockOnFailure bool `json:"block_on_failure"` // Don't upload MediaInfo and hashes of releases that fail verification
+}
+
+type ContentDetectionConfig struct {
+	Enabled bool `json:"enabled"` // Infer the category from the files (magic bytes, MediaInfo tracks) if mappings and regex rules don't match
+}
+
+type ReleaseNameConfig struct {
+	Enabled       bool     `json:"enabled"`                 // Validate the torrent name before uploading
+	StripJunk     bool     `json:"strip_junk"`              // Remove website prefixes like [www.site.com] and tracker tags
+	ReplaceSpaces bool     `json:"replace_spaces"`          // Replace spaces with dots
+	Fallback      bool     `json:"fallback"`                // Use the folder or NFO derived name if the torrent name is not valid
+	SkipInvalid   bool     `json:"skip_invalid"`            // Don't upload releases without a valid name, otherwise they are only flagged
+	JunkPatterns  []string `json:"junk_patterns,omitempty"` // Additional regexes that are removed from the name
+}
+
+type LoggingConfig struct {
+	Level      string `json:"level"`       // debug, info, warn or error
+	Format     string `json:"format"`      // text or json
+	File       string `json:"file"`        // Relative to the executable directory, empty disables the log file
+	MaxSizeMB  int    `json:"max_size_mb"` // Rotate the log file once it exceeds this size
+	MaxBackups int    `json:"max_backups"` // Number of rotated log files to keep
+}
+
+// QBittorrentConfig configures the WebUI API access used by serve mode
+type QBittorrentConfig struct {
+	BaseURL      string `json:"base_url"`
+	Username     string `json:"username"`
+	Password     string `json:"password"`
+	PollInterval int    `json:"poll_interval"` // Seconds between polls
+}
+
+// Default parallelism for season packs
+const (
+	defaultHashWorkers   = 1
+	defaultUploadWorkers = 3
+)
+
+// Handling of specials (S00Exx, OVA, SP01) in season packs
+const (
+	specialsSplit = "split"
+	specialsPack  = "pack"
+)
+
+// Season pack strategies
+const (
+	seasonPackSplit = "split"
+	seasonPackWhole = "pack"
+	seasonPackBoth  = "both"
+)
+
+// validateSeasonPackConfig checks specials and strategies, empty values fall back to splitting
+func validateSeasonPackConfig(seasonPack *SeasonPackConfig) error {
+	switch seasonPack.Specials {
+	case "":
+		seasonPack.Specials = specialsSplit
+	case specialsSplit, specialsPack:
+	default:
+		return fmt.Errorf("specials '%s' (valid: %s, %s)", seasonPack.Specials, specialsSplit, specialsPack)
+	}
+
+	validStrategy := func(strategy string) bool {
+		return strategy == seasonPackSplit || strategy == seasonPackWhole || strategy == seasonPackBoth
+	}
+	if seasonPack.Strategy == "" {
+		seasonPack.Strategy = seasonPackSplit
+	}
+	if !validStrategy(seasonPack.Strategy) {
+		return fmt.Errorf("strategy '%s' (valid: %s, %s, %s)", seasonPack.Strategy, seasonPackSplit, seasonPackWhole, seasonPackBoth)
+	}
+	for category, strategy := range seasonPack.CategoryStrategies {
+		if !validStrategy(strategy) {
+			return fmt.Errorf("category_strategies: category '%s' has strategy '%s' (valid: %s, %s, %s)", category, strategy, seasonPackSplit, seasonPackWhole, seasonPackBoth)
+		}
+	}
+	return nil
+}
+
 // Valid CrowdNFO categories
 var validCategories = []string{"Movies", "TV", "Games", "Software", "Music", "Audiobooks", "Books", "Other"}
 
-// Built-in regex patterns for category detection
-var categoryRegexPatterns = []struct {
-	Pattern  string
-	Category string
-}{
-	{`(?i)\b(audiobook|abook|abookde|hörbuch|hoerbuch|horbuch|m4b)\b`, "Audiobooks"},
-	{`(?i)\b(ebook|epaper|pdf|epub|mobi)\b`, "Books"},
-	{`(?i)\b((s\d{1,4}e\d{1,4})|(s\d{1,4})|(e\d{1,4})|season|staffel|episode|folge|(\d{4}-\d{2}-\d{2}))\b`, "TV"},
-	{`(?i)\b(elamigos|gog|xbox|xbox360|x360|ps\d|nintendo|nsw|amiga|atari|wii[u]?)\b`, "Games"},
-	{`(?i)\b(patch|crack|cracked|keygen|keymaker|keyfilemaker|x64|dvt|btcr|macos)\b`, "Software"},
-	{`(?i)\b((\d{3,4}[pi])|bluray|dvdrip|webrip|hdtv|bdrip|dvd|remux|mpeg[-]?2|vc[-]?1|avc|hevc|([xh][. ]?26[456]))\b`, "Movies"},
-	{`(?i)\b(mp3|flac|webflac|aac|wav|album|artist|discography|single|vinyl|cd|\d+bit|\d+khz)\b`, "Music"},
+// CategoryRule maps release names matching Pattern to a CrowdNFO category, rules with a higher priority are checked first
+type CategoryRule struct {
+	Name     string `json:"name,omitempty"`     // A rule with the name of a built-in rule replaces it
+	Pattern  string `json:"pattern"`            // Go regular expression matched against the release name
+	Category string `json:"category"`           // CrowdNFO category
+	Priority int    `json:"priority"`           // Built-in rules use 100 to 700
+	Disabled bool   `json:"disabled,omitempty"` // Disables the built-in rule with the same name
+
+	regex *regexp.Regexp
+}
+
+// Built-in regex rules for category detection
+var builtInCategoryRules = []CategoryRule{
+	{Name: "audiobooks", Pattern: `(?i)\b(audiobook|abook|abookde|hörbuch|hoerbuch|horbuch|m4b)\b`, Category: "Audiobooks", Priority: 700},
+	{Name: "books", Pattern: `(?i)\b(ebook|epaper|pdf|epub|mobi)\b`, Category: "Books", Priority: 600},
+	{Name: "tv", Pattern: `(?i)\b((s\d{1,4}e\d{1,4})|(s\d{1,4})|(e\d{1,4})|season|staffel|episode|folge|(\d{4}[-.]\d{2}[-.]\d{2})|(\d{2}[-.]\d{2}[-.]\d{4}))\b`, Category: "TV", Priority: 500},
+	{Name: "games", Pattern: `(?i)\b(elamigos|gog|xbox|xbox360|x360|ps\d|nintendo|nsw|amiga|atari|wii[u]?)\b`, Category: "Games", Priority: 400},
+	{Name: "software", Pattern: `(?i)\b(patch|crack|cracked|keygen|keymaker|keyfilemaker|x64|dvt|btcr|macos)\b`, Category: "Software", Priority: 300},
+	{Name: "movies", Pattern: `(?i)\b((\d{3,4}[pi])|bluray|dvdrip|webrip|hdtv|bdrip|dvd|remux|mpeg[-]?2|vc[-]?1|avc|hevc|([xh][. ]?26[456]))\b`, Category: "Movies", Priority: 200},
+	{Name: "music", Pattern: `(?i)\b(mp3|flac|webflac|aac|wav|album|artist|discography|single|vinyl|cd|\d+bit|\d+khz)\b`, Category: "Music", Priority: 100},
+}
+
+// defaultCategoryRules are the compiled built-in rules, used if the config was not loaded via loadConfig
+var defaultCategoryRules, _ = compileCategoryRules(nil)
+
+// compileCategoryRules validates and compiles the rules from the config and merges them with the built-in rules
+func compileCategoryRules(configRules []CategoryRule) ([]CategoryRule, error) {
+	rules := make([]CategoryRule, 0, len(configRules)+len(builtInCategoryRules))
+	overridden := make(map[string]bool)
+
+	for i, rule := range configRules {
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		} else {
+			overridden[strings.ToLower(rule.Name)] = true
+		}
+		if rule.Disabled {
+			continue
+		}
+
+		if !isValidCategory(rule.Category) {
+			return nil, fmt.Errorf("category rule %s: invalid CrowdNFO category '%s' (valid: %s)", name, rule.Category, strings.Join(validCategories, ", "))
+		}
+		if rule.Pattern == "" {
+			return nil, fmt.Errorf("category rule %s: pattern is empty", name)
+		}
+		regex, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("category rule %s: invalid pattern: %v", name, err)
+		}
+		rule.regex = regex
+		rules = append(rules, rule)
+	}
+
+	for _, rule := range builtInCategoryRules {
+		if overridden[rule.Name] {
+			continue
+		}
+		rule.regex = regexp.MustCompile(rule.Pattern)
+		rules = append(rules, rule)
+	}
+
+	// Config rules come first, so they win against built-in rules with the same priority
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].Priority > rules[j].Priority
+	})
+
+	return rules, nil
+}
+
+// label returns the name of the rule, or its pattern for unnamed rules
+func (r CategoryRule) label() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return r.Pattern
+}
+
+// getCategoryRules returns the compiled category rules of the config
+func getCategoryRules(config *Config) []CategoryRule {
+	if config.categoryRules == nil {
+		return defaultCategoryRules
+	}
+	return config.categoryRules
 }
 
 func loadConfig() (*Config, error) {
@@ -66,6 +258,7 @@ func loadConfig() (*Config, error) {
 			MediaInfoPath:   "", // Optional - will be auto-detected if empty
 			MaxHashFileSize: "", // Optional - no limit by default, use "0" to disable, or "5GB"/"800MB" to set limit
 			VerifySSL:       true, // Verify SSL certificates by default
+			DryRun:          false,
 			CategoryMappings: map[string][]string{
 				"Movies":     []string{"movies", "movie", "radarr", "film"},
 				"TV":         []string{"tv", "television", "sonarr", "series", "shows", "serien", "anime"},
@@ -89,6 +282,50 @@ func loadConfig() (*Config, error) {
 				Enabled: false,
 				BaseURL: "http://localhost:5050",
 			},
+			UploadQueue: UploadQueueConfig{
+				Enabled:     true,
+				MaxAttempts: defaultQueueMaxAttempts,
+			},
+			SeasonPack: SeasonPackConfig{
+				HashWorkers:   defaultHashWorkers,
+				UploadWorkers: defaultUploadWorkers,
+				Specials:      specialsSplit,
+				Strategy:      seasonPackSplit,
+			},
+			Hashing: HashingConfig{
+				Algorithms:       []string{},
+				TimeoutSeconds:   0,
+				RateLimitMBps:    0,
+				ProgressInterval: defaultHashProgressIntervalSec,
+				Cache:            true,
+			},
+			Verification: VerificationConfig{
+				Enabled:        true,
+				BlockOnFailure: true,
+			},
+			ContentDetection: ContentDetectionConfig{
+				Enabled: true,
+			},
+			ReleaseNames: ReleaseNameConfig{
+				Enabled:       true,
+				StripJunk:     true,
+				ReplaceSpaces: false,
+				Fallback:      true,
+				SkipInvalid:   false,
+			},
+			QBittorrent: QBittorrentConfig{
+				BaseURL:      "http://localhost:8080",
+				Username:     "admin",
+				Password:     "",
+				PollInterval: defaultPollInterval,
+			},
+			Logging: LoggingConfig{
+				Level:      "info",
+				Format:     "text",
+				File:       defaultLogFile,
+				MaxSizeMB:  defaultLogMaxSizeMB,
+				MaxBackups: defaultLogMaxBackups,
+			},
 		}
 
 		configData, err := json.MarshalIndent(defaultConfig, "", "  ")
@@ -100,7 +337,7 @@ func loadConfig() (*Config, error) {
 			return nil, err
 		}
 
-		log.Printf("Created default config file at %s. Please update your API key.", configPath)
+		logInfof("Created default config file at %s. Please update your API key.", configPath)
 		return nil, fmt.Errorf("please update the API key in %s", configPath)
 	}
 
@@ -114,36 +351,135 @@ func loadConfig() (*Config, error) {
 		return nil, err
 	}
 
-	if config.APIKey == "YOUR_API_KEY_HERE" {
+	if config.APIKey == "YOUR_API_KEY_HERE" && usesCrowdNFO(&config) {
 		return nil, fmt.Errorf("please update the API key in %s", configPath)
 	}
 
+	if err := initLogging(&config); err != nil {
+		logWarnf("⚠️ %v", err)
+	}
+
+	config.categoryRules, err = compileCategoryRules(config.CategoryRules)
+	if err != nil {
+		return nil, fmt.Errorf("invalid category_rules in %s: %v", configPath, err)
+	}
+	if err := validateCategoryMappings(config.TagMappings); err != nil {
+		return nil, fmt.Errorf("invalid tag_mappings in %s: %v", configPath, err)
+	}
+	if err := validateCategoryMappings(config.TrackerMappings); err != nil {
+		return nil, fmt.Errorf("invalid tracker_mappings in %s: %v", configPath, err)
+	}
+	config.junkPatterns, err = compileJunkPatterns(config.ReleaseNames.JunkPatterns)
+	if err != nil {
+		return nil, fmt.Errorf("invalid release_names in %s: %v", configPath, err)
+	}
+	if err := validateUploaders(config.Uploaders); err != nil {
+		return nil, fmt.Errorf("invalid uploaders in %s: %v", configPath, err)
+	}
+	if err := validateSeasonPackConfig(&config.SeasonPack); err != nil {
+		return nil, fmt.Errorf("invalid season_pack in %s: %v", configPath, err)
+	}
+	if err := compileFilterRules(config.Filters.Include); err != nil {
+		return nil, fmt.Errorf("invalid filters.include in %s: %v", configPath, err)
+	}
+	if err := compileFilterRules(config.Filters.Exclude); err != nil {
+		return nil, fmt.Errorf("invalid filters.exclude in %s: %v", configPath, err)
+	}
+
+	// The --dry-run flag overrides the config file
+	if dryRunFlag {
+		config.DryRun = true
+	}
+	if config.DryRun {
+		logInfof("🧪 Dry-run mode enabled - nothing will be sent to CrowdNFO")
+	}
+
 	return &config, nil
 }
 
-// mapCategory maps SABnzbd category to CrowdNFO category
-func mapCategory(config *Config, sabnzbdCategory, releaseName string) string {
+// CategoryResolution describes which CrowdNFO category was chosen and how
+type CategoryResolution struct {
+	Input    string `json:"input"`             // qBittorrent category
+	Tags     string `json:"tags,omitempty"`    // qBittorrent tags
+	Tracker  string `json:"tracker,omitempty"` // qBittorrent tracker
+	Category string `json:"category"`          // CrowdNFO category, empty if not detected
+	Source   string `json:"source"`            // config_mapping, built_in, tag_mapping, tracker_mapping, regex, release_info, content or none
+	Rule     string `json:"rule,omitempty"`    // Matching mapping entry, tag, tracker host, regex pattern or content summary
+}
+
+// resolveCategory determines the CrowdNFO category and records how it was chosen
+// Precedence: category_mappings, built-in category names, tag_mappings, tracker_mappings, regex rules on the release name,
+// the parsed release name, content detection on the release files
+func resolveCategory(log *Logger, config *Config, qbtArgs QBittorrentArgs, releaseName string, files []string, mediaInfoJSON []byte) CategoryResolution {
+	resolution := CategoryResolution{Input: qbtArgs.Category, Tags: qbtArgs.Tags, Tracker: qbtArgs.Tracker}
+
+	if category, rule, source := matchCategoryByName(log, config, qbtArgs.Category); category != "" {
+		resolution.Category, resolution.Source, resolution.Rule = category, source, rule
+		return resolution
+	}
+
+	if category, tag := matchCategoryByTags(config, qbtArgs.Tags); category != "" {
+		log.Infof("🏷️ Category mapped via tag '%s' -> '%s'", tag, category)
+		resolution.Category, resolution.Source, resolution.Rule = category, "tag_mapping", tag
+		return resolution
+	}
+
+	if category, host := matchCategoryByTracker(config, qbtArgs.Tracker); category != "" {
+		log.Infof("🏷️ Category mapped via tracker '%s' -> '%s'", host, category)
+		resolution.Category, resolution.Source, resolution.Rule = category, "tracker_mapping", host
+		return resolution
+	}
+
+	// If no mapping found, try regex on release name
+	if category, pattern := matchCategoryByRegex(log, config, releaseName); category != "" {
+		resolution.Category, resolution.Source, resolution.Rule = category, "regex", pattern
+		return resolution
+	}
+
+	// Regex rules can be disabled or overridden, the parsed release name still tells episodes and movies apart
+	if category, fields := matchCategoryByReleaseInfo(releaseName); category != "" {
+		log.Infof("🏷️ Category detected from release name (%s) -> '%s'", fields, category)
+		resolution.Category, resolution.Source, resolution.Rule = category, "release_info", fields
+		return resolution
+	}
+
+	// Name is ambiguous, look at the files themselves
+	if config.ContentDetection.Enabled {
+		if category, reason := classifyContent(log, files, mediaInfoJSON); category != "" {
+			log.Infof("🔍 Category detected from content (%s) -> '%s'", reason, category)
+			resolution.Category, resolution.Source, resolution.Rule = category, "content", reason
+			return resolution
+		}
+	}
+
+	log.Warnf("⚠️ Could not detect category")
+	resolution.Source = "none"
+	return resolution
+}
+
+// matchCategoryByName maps the qBittorrent category via category_mappings or the built-in category names
+func matchCategoryByName(log *Logger, config *Config, sabnzbdCategory string) (category, rule, source string) {
 	// Clean up the category
-	category := strings.TrimSpace(sabnzbdCategory)
+	category = strings.TrimSpace(sabnzbdCategory)
 
 	// Check if category is empty or wildcard
 	if category == "" || category == "*" {
-		return matchCategoryByRegex(releaseName)
+		return "", "", ""
 	}
 
 	// First try custom mappings from config - search through all CrowdNFO categories
 	if config.CategoryMappings != nil {
 		for crowdNFOCategory, sabnzbdCategories := range config.CategoryMappings {
 			if !isValidCategory(crowdNFOCategory) {
-				log.Printf("⚠️ Invalid CrowdNFO category in config: '%s', skipping", crowdNFOCategory)
+				log.Warnf("⚠️ Invalid CrowdNFO category in config: '%s', skipping", crowdNFOCategory)
 				continue
 			}
 
 			// Check if our SABnzbd category is in the list for this CrowdNFO category
 			for _, sabnzbdCat := range sabnzbdCategories {
 				if strings.EqualFold(category, sabnzbdCat) {
-					log.Printf("🏷️ Category mapped via config -> '%s'", crowdNFOCategory)
-					return crowdNFOCategory
+					log.Infof("🏷️ Category mapped via config -> '%s'", crowdNFOCategory)
+					return crowdNFOCategory, sabnzbdCat, "config_mapping"
 				}
 			}
 		}
@@ -151,33 +487,120 @@ func mapCategory(config *Config, sabnzbdCategory, releaseName string) string {
 
 	// Try standard mapping (case-insensitive)
 	for _, validCat := range validCategories {
-		if strings.EqualFold(sabnzbdCategory, validCat) {
-			log.Printf("🏷️ Category mapped via built-in mapping -> '%s'", validCat)
-			return validCat
+		if strings.EqualFold(category, validCat) {
+			log.Infof("🏷️ Category mapped via built-in mapping -> '%s'", validCat)
+			return validCat, validCat, "built_in"
 		}
 	}
 
-	// If no direct mapping found, try regex on release name
-	return matchCategoryByRegex(releaseName)
+	return "", "", ""
 }
 
-// matchCategoryByRegex tries to determine category from release name using built-in regex patterns
-func matchCategoryByRegex(releaseName string) string {
-	for _, regexRule := range categoryRegexPatterns {
-		regex, err := regexp.Compile(regexRule.Pattern)
-		if err != nil {
-			log.Printf("⚠️ Invalid built-in regex pattern '%s': %v", regexRule.Pattern, err)
+// matchCategoryByTags maps the comma separated qBittorrent tags via tag_mappings, the first mapped tag wins
+func matchCategoryByTags(config *Config, tags string) (string, string) {
+	if len(config.TagMappings) == 0 {
+		return "", ""
+	}
+
+	for _, tag := range strings.Split(tags, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
 			continue
 		}
+		// Walk the categories in a fixed order so the result doesn't depend on map iteration
+		for _, crowdNFOCategory := range validCategories {
+			for _, mappedTag := range config.TagMappings[crowdNFOCategory] {
+				if strings.EqualFold(tag, strings.TrimSpace(mappedTag)) {
+					return crowdNFOCategory, tag
+				}
+			}
+		}
+	}
+
+	return "", ""
+}
+
+// matchCategoryByTracker maps the tracker hostname via tracker_mappings, an entry also matches its subdomains
+func matchCategoryByTracker(config *Config, tracker string) (string, string) {
+	if len(config.TrackerMappings) == 0 {
+		return "", ""
+	}
+
+	host := trackerHost(tracker)
+	if host == "" {
+		return "", ""
+	}
+
+	for _, crowdNFOCategory := range validCategories {
+		for _, mappedTracker := range config.TrackerMappings[crowdNFOCategory] {
+			if trackerMatches(host, mappedTracker) {
+				return crowdNFOCategory, host
+			}
+		}
+	}
+
+	return "", ""
+}
+
+// trackerHost extracts the lowercase hostname from a tracker URL such as https://tracker.example.org:443/announce
+// Plain hostnames without scheme are accepted as well
+func trackerHost(tracker string) string {
+	tracker = strings.TrimSpace(tracker)
+	if tracker == "" {
+		return ""
+	}
+	if !strings.Contains(tracker, "://") {
+		tracker = "//" + tracker
+	}
+
+	parsed, err := url.Parse(tracker)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(strings.ToLower(parsed.Hostname()), ".")
+}
+
+// trackerMatches checks if the tracker hostname equals the configured tracker or is one of its subdomains
+func trackerMatches(host, configuredTracker string) bool {
+	configuredHost := trackerHost(configuredTracker)
+	return configuredHost != "" && (host == configuredHost || strings.HasSuffix(host, "."+configuredHost))
+}
+
+// validateCategoryMappings checks that all keys of a tag or tracker mapping are CrowdNFO categories
+func validateCategoryMappings(mappings map[string][]string) error {
+	for crowdNFOCategory := range mappings {
+		if !isValidCategory(crowdNFOCategory) {
+			return fmt.Errorf("invalid CrowdNFO category '%s' (valid: %s)", crowdNFOCategory, strings.Join(validCategories, ", "))
+		}
+	}
+	return nil
+}
 
-		if regex.MatchString(releaseName) {
-			log.Printf("🏷️ Category matched via regex -> '%s'", regexRule.Category)
-			return regexRule.Category
+// matchCategoryByRegex tries to determine category from release name using the configured and built-in regex rules
+func matchCategoryByRegex(log *Logger, config *Config, releaseName string) (string, string) {
+	for _, rule := range getCategoryRules(config) {
+		if rule.regex.MatchString(releaseName) {
+			log.Infof("🏷️ Category matched via regex rule '%s' -> '%s'", rule.label(), rule.Category)
+			return rule.Category, rule.Pattern
 		}
 	}
 
-	log.Printf("⚠️ Could not detect category")
-	return ""
+	return "", ""
+}
+
+// matchCategoryByReleaseInfo classifies by the parsed release name: seasons, episodes and air dates are TV,
+// a video resolution or codec is Movies
+func matchCategoryByReleaseInfo(releaseName string) (string, string) {
+	info := parseReleaseName(releaseName)
+	switch {
+	case info.seasonToken != "" || info.Complete || info.IsEpisode():
+		return "TV", "season/episode"
+	case info.Resolution != "":
+		return "Movies", "resolution " + info.Resolution
+	case info.Codec != "":
+		return "Movies", "codec " + info.Codec
+	}
+	return "", ""
 }
 
 // isValidCategory checks if category is valid for CrowdNFO
@@ -210,7 +633,7 @@ func isCategoryExcluded(config *Config, category string) bool {
 func getCurrentDir() string {
 	dir, err := os.Executable()
 	if err != nil {
-		log.Fatal("Failed to get executable directory:", err)
+		logFatalf("Failed to get executable directory: %v", err)
 	}
 	return filepath.Dir(dir)
 }
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/h2non/filetype"
+)
+
+// Content kinds detected from magic bytes or file extensions
+const (
+	contentVideo      = "video"
+	contentAudio      = "audio"
+	contentAudiobook  = "audiobook"
+	contentMedia      = "media" // Container that can hold video or audio (Matroska, MP4, Ogg, ASF)
+	contentEbook      = "ebook"
+	contentExecutable = "executable"
+	contentDiscImage  = "disc_image"
+	contentArchive    = "archive"
+)
+
+// contentSniffSize covers the ISO 9660 volume descriptor, the furthest signature filetype checks
+const contentSniffSize = 32774
+
+var (
+	ebookExtensions      = []string{".epub", ".mobi", ".azw", ".azw3", ".pdf"}
+	executableExtensions = []string{".exe", ".msi", ".dmg", ".pkg", ".apk", ".appimage"}
+	archiveExtensions    = []string{".zip", ".rar", ".7z"}
+
+	// Files that only ship with games
+	gameMarkerFiles      = []string{"steam_api.dll", "steam_api64.dll", "steam_emu.ini", "galaxy.dll", "galaxy64.dll"}
+	gameMarkerExtensions = []string{".pak", ".vpk", ".bsa", ".ba2", ".wad", ".bik"}
+
+	contentEpisodePattern = regexp.MustCompile(`(?i)S\d{1,4}E\d{1,4}`)
+)
+
+// sniffContentKind reads the first bytes of a file and returns its kind, or an empty string if it is unknown
+func sniffContentKind(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	header := make([]byte, contentSniffSize)
+	n, _ := io.ReadFull(file, header)
+	header = header[:n]
+
+	kind, _ := filetype.Match(header)
+	switch kind.Extension {
+	case "mkv", "webm", "mp4", "mov", "ogg", "wmv":
+		// Generic containers that can hold video or audio (Matroska, MP4, Ogg, ASF)
+		return contentMedia
+	case "pdf", "epub":
+		return contentEbook
+	case "zip", "rar", "7z", "tar", "gz", "bz2", "xz":
+		return contentArchive
+	case "exe", "elf", "macho":
+		return contentExecutable
+	case "iso":
+		return contentDiscImage
+	}
+	switch kind.MIME.Type {
+	case "video":
+		return contentVideo
+	case "audio":
+		return contentAudio
+	}
+
+	// Formats filetype doesn't know
+	hasPrefix := func(offset int, magic string) bool {
+		return len(header) >= offset+len(magic) && string(header[offset:offset+len(magic)]) == magic
+	}
+	switch {
+	case hasPrefix(4, "ftypM4B "):
+		return contentAudiobook
+	case hasPrefix(4, "ftyp"):
+		return contentMedia
+	case hasPrefix(60, "BOOKMOBI"):
+		return contentEbook
+	case len(header) > 188 && header[0] == 0x47 && header[188] == 0x47:
+		return contentVideo // MPEG transport stream
+	case len(header) >= 2 && header[0] == 0xff && header[1]&0xe0 == 0xe0:
+		return contentAudio // MPEG audio / ADTS frame sync
+	}
+
+	return ""
+}
+
+// extensionContentKind returns the kind a file extension claims, or an empty string if it is unknown
+func extensionContentKind(path string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	switch {
+	case ext == ".m4b":
+		return contentAudiobook
+	case isAudioExtension(ext):
+		return contentAudio
+	case containsString(mediaInfoExtensions, ext):
+		return contentVideo
+	case containsString(ebookExtensions, ext):
+		return contentEbook
+	case containsString(executableExtensions, ext):
+		return contentExecutable
+	case containsString(hashOnlyExtensions, ext):
+		return contentDiscImage
+	case containsString(archiveExtensions, ext):
+		return contentArchive
+	}
+	return ""
+}
+
+// isMediaKind reports whether the kind is playable by MediaInfo
+func isMediaKind(kind string) bool {
+	return kind == contentVideo || kind == contentAudio || kind == contentAudiobook || kind == contentMedia
+}
+
+// detectContentKind combines magic bytes and extension, misnamed files are reported via the second return value
+func detectContentKind(path string) (kind string, misnamed bool) {
+	extKind := extensionContentKind(path)
+	sniffed := sniffContentKind(path)
+
+	switch {
+	case sniffed == "":
+		return extKind, false
+	case sniffed == contentMedia:
+		// Generic containers don't tell video and audio apart, trust the extension for those
+		if isMediaKind(extKind) {
+			return extKind, false
+		}
+		return contentVideo, extKind != ""
+	case extKind == "" || sniffed == extKind:
+		return sniffed, false
+	case isMediaKind(sniffed) && isMediaKind(extKind):
+		// e.g. an MP3 stream in a .m4a file, the content decides but it's not worth a warning
+		return sniffed, false
+	default:
+		return sniffed, true
+	}
+}
+
+// isPlayableMediaFile checks that a file picked by its extension actually contains audio or video
+func isPlayableMediaFile(log *Logger, path string) bool {
+	kind, misnamed := detectContentKind(path)
+	if misnamed {
+		log.Warnf("⚠️ %s has a misleading extension, content looks like %s", filepath.Base(path), kind)
+	}
+	return kind == "" || isMediaKind(kind)
+}
+
+// listReleaseFiles returns all files of a release directory, or the path itself for single file torrents
+func listReleaseFiles(path string) []string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+	if !info.IsDir() {
+		return []string{path}
+	}
+
+	var files []string
+	filepath.WalkDir(path, func(filePath string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			files = append(files, filePath)
+		}
+		return nil
+	})
+	return files
+}
+
+// mediaInfoTrackTypes returns the track types (Video, Audio, Text, ...) of a MediaInfo JSON document
+func mediaInfoTrackTypes(mediaInfoJSON []byte) map[string]bool {
+	types := make(map[string]bool)
+	if len(mediaInfoJSON) == 0 {
+		return types
+	}
+
+	var data MediaInfoData
+	if err := json.Unmarshal(mediaInfoJSON, &data); err != nil {
+		return types
+	}
+	for _, track := range data.Media.Track {
+		types[track.Type] = true
+	}
+	return types
+}
+
+// classifyContent infers the CrowdNFO category from the files of a release
+// The file kinds are detected via magic bytes, MediaInfo tracks refine video vs. audio for the main media file
+// Returns an empty category if the content is ambiguous, e.g. only archives
+func classifyContent(log *Logger, files []string, mediaInfoJSON []byte) (category, reason string) {
+	sizes := make(map[string]int64)
+	counts := make(map[string]int)
+	var episodeFiles int
+	var gameMarkers bool
+
+	for _, path := range files {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+
+		kind, misnamed := detectContentKind(path)
+		if misnamed {
+			log.Debugf("⚠️ %s has a misleading extension, content looks like %s", filepath.Base(path), kind)
+		}
+		if kind == "" {
+			continue
+		}
+		sizes[kind] += info.Size()
+		counts[kind]++
+
+		if kind == contentVideo && contentEpisodePattern.MatchString(filepath.Base(path)) {
+			episodeFiles++
+		}
+		name := strings.ToLower(filepath.Base(path))
+		if containsString(gameMarkerFiles, name) || containsString(gameMarkerExtensions, filepath.Ext(name)) {
+			gameMarkers = true
+		}
+	}
+
+	// Video containers without a video track are audio releases (e.g. .mka or audio-only .mp4)
+	tracks := mediaInfoTrackTypes(mediaInfoJSON)
+	if len(tracks) > 0 && !tracks["Video"] && tracks["Audio"] && sizes[contentVideo] > 0 {
+		sizes[contentAudio] += sizes[contentVideo]
+		counts[contentAudio] += counts[contentVideo]
+		delete(sizes, contentVideo)
+		delete(counts, contentVideo)
+	}
+
+	// Archives are ignored, packed scene releases can't be classified by content
+	var dominant string
+	for _, kind := range []string{contentVideo, contentAudiobook, contentAudio, contentEbook, contentDiscImage, contentExecutable} {
+		if sizes[kind] > sizes[dominant] {
+			dominant = kind
+		}
+	}
+	if dominant == "" {
+		return "", ""
+	}
+	reason = fmt.Sprintf("%d %s file(s), %s", counts[dominant], strings.ReplaceAll(dominant, "_", " "), formatBytes(sizes[dominant]))
+
+	switch dominant {
+	case contentVideo:
+		if episodeFiles > 0 || counts[contentVideo] >= 3 {
+			return "TV", reason
+		}
+		return "Movies", reason
+	case contentAudiobook:
+		return "Audiobooks", reason
+	case contentAudio:
+		if counts[contentAudiobook] > 0 {
+			return "Audiobooks", reason
+		}
+		return "Music", reason
+	case contentEbook:
+		return "Books", reason
+	default:
+		if gameMarkers {
+			return "Games", reason + ", game files"
+		}
+		return "Software", reason
+	}
+}
+
+// containsString checks if the list contains the value
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -1,20 +1,21 @@
 package main
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
+	"encoding/json"
 	"fmt"
-	"io"
 	"io/fs"
-	"log"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 var (
+	completeSeriesPattern = regexp.MustCompile(`(?i)[._ ]?\bCOMPLETE[._ ](SERIES|SEASONS)\b`)
+	seasonFolderPattern   = regexp.MustCompile(`(?i)^(?:season|staffel|series|s)[ ._-]?(\d{1,4})$`)
+
 	mediaInfoExtensions = []string{".mkv", ".mp4", ".avi", ".mov", ".wmv", ".flv", ".mpeg", ".mpg", ".webm", ".m4v", ".divx", ".xvid", ".mp3", ".aac", ".flac", ".wav", ".ogg", ".opus", ".m4a", ".mka", ".wma", ".alac", ".dts", ".dtshd", ".ac3", ".eac3", ".ec3", ".m4b"}
 	hashOnlyExtensions  = []string{".iso", ".img"}
 )
@@ -147,23 +148,8 @@ func findNFOFile(dir string) (string, error) {
 	return nfoFile, err
 }
 
-func calculateSHA256(filePath string) (string, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return "", err
-	}
-	defer file.Close()
-
-	hash := sha256.New()
-	if _, err := io.Copy(hash, file); err != nil {
-		return "", err
-	}
-
-	return hex.EncodeToString(hash.Sum(nil)), nil
-}
-
 // shouldCalculateHash checks if hash should be calculated based on file size and config
-func shouldCalculateHash(config *Config, filePath string) (bool, error) {
+func shouldCalculateHash(log *Logger, config *Config, filePath string) (bool, error) {
 	// If max_hash_file_size is not set or empty, always calculate hash
 	if config.MaxHashFileSize == "" {
 		return true, nil
@@ -177,7 +163,7 @@ func shouldCalculateHash(config *Config, filePath string) (bool, error) {
 	// Parse the size limit from config with unit support (MB/GB)
 	maxSizeBytes, err := parseSizeWithUnit(config.MaxHashFileSize)
 	if err != nil {
-		log.Printf("⚠️ Invalid max_hash_file_size format: %s, ignoring limit", config.MaxHashFileSize)
+		log.Warnf("⚠️ Invalid max_hash_file_size format: %s, ignoring limit", config.MaxHashFileSize)
 		return true, nil
 	}
 
@@ -190,7 +176,7 @@ func shouldCalculateHash(config *Config, filePath string) (bool, error) {
 	if fileInfo.Size() > maxSizeBytes {
 		fileSizeGB := float64(fileInfo.Size()) / (1024 * 1024 * 1024)
 		maxSizeGB := float64(maxSizeBytes) / (1024 * 1024 * 1024)
-		log.Printf("⏭️ Skipping hash calculation (%.2f GB > %.2f GB limit)", fileSizeGB, maxSizeGB)
+		log.Infof("⏭️ Skipping hash calculation (%.2f GB > %.2f GB limit)", fileSizeGB, maxSizeGB)
 		return false, nil
 	}
 
@@ -238,7 +224,9 @@ type VideoFile struct {
 
 type EpisodeInfo struct {
 	VideoFile   VideoFile
-	EpisodeNum  string // "E01", "E19" etc.
+	EpisodeNum  string // "E01", "E19" etc., multi-episode files list all episodes ("E01E02")
+	Episodes    []int
+	Special     bool // S00Exx, OVA or SP01, see season_pack.specials
 	ReleaseName string
 	NFOFile     string
 }
@@ -339,14 +327,14 @@ func createFileList(dir, releaseName string) ([]FileListEntry, error) {
 }
 
 // createEpisodeFileList creates a file list for a specific episode directory or related files
-func createEpisodeFileList(episodeInfo EpisodeInfo) ([]FileListEntry, error) {
+func createEpisodeFileList(log *Logger, episodeInfo EpisodeInfo) ([]FileListEntry, error) {
 	// Get the parent directory (season pack directory)
 	seasonPackDir := filepath.Dir(episodeInfo.VideoFile.Dir)
 
 	// Safety check: if parent directory calculation results in root or invalid path,
 	// fallback to treating as single file
 	if seasonPackDir == "/" || seasonPackDir == "." || seasonPackDir == episodeInfo.VideoFile.Dir {
-		log.Printf("⚠️ Invalid season pack directory detected (%s), falling back to single file processing", seasonPackDir)
+		log.Warnf("⚠️ Invalid season pack directory detected (%s), falling back to single file processing", seasonPackDir)
 		return createFileList(episodeInfo.VideoFile.Dir, episodeInfo.ReleaseName)
 	}
 
@@ -367,7 +355,7 @@ func createEpisodeFileList(episodeInfo EpisodeInfo) ([]FileListEntry, error) {
 
 	// If mainDirVideoCount is 0, something is wrong - treat as single video file
 	if mainDirVideoCount == 0 {
-		log.Printf("⚠️ No videos found in the expected directory structure, treating as single video file: %s", episodeInfo.VideoFile.Name)
+		log.Warnf("⚠️ No videos found in the expected directory structure, treating as single video file: %s", episodeInfo.VideoFile.Name)
 		return createFileList(episodeInfo.VideoFile.Dir, episodeInfo.ReleaseName)
 	}
 
@@ -377,7 +365,7 @@ func createEpisodeFileList(episodeInfo EpisodeInfo) ([]FileListEntry, error) {
 		// Videos are in main directory - find only related files for this specific episode
 		videoBaseName := strings.TrimSuffix(episodeInfo.VideoFile.Name, filepath.Ext(episodeInfo.VideoFile.Name))
 
-		entries, err := findRelatedFiles(episodeInfo.VideoFile.Dir, videoBaseName, episodeInfo.VideoFile.Path)
+		entries, err := findRelatedFiles(log, episodeInfo.VideoFile.Dir, videoBaseName, episodeInfo.VideoFile.Path)
 		if err != nil {
 			return nil, err
 		}
@@ -389,7 +377,7 @@ func createEpisodeFileList(episodeInfo EpisodeInfo) ([]FileListEntry, error) {
 }
 
 // findRelatedFiles finds all files that are related to a specific video file
-func findRelatedFiles(dir, videoBaseName, videoPath string) ([]FileListEntry, error) {
+func findRelatedFiles(log *Logger, dir, videoBaseName, videoPath string) ([]FileListEntry, error) {
 	var entries []FileListEntry
 	baseDir := filepath.Clean(dir)
 
@@ -408,7 +396,7 @@ func findRelatedFiles(dir, videoBaseName, videoPath string) ([]FileListEntry, er
 	videoRelPath = filepath.ToSlash(videoRelPath)
 	if videoRelPath == "." {
 		videoRelPath = filepath.Base(videoPath)
-		log.Printf("⚠️ Fixed invalid video file path '.' to '%s' for file: %s (baseDir: %s)", videoRelPath, videoPath, baseDir)
+		log.Warnf("⚠️ Fixed invalid video file path '.' to '%s' for file: %s (baseDir: %s)", videoRelPath, videoPath, baseDir)
 	}
 
 	entries = append(entries, FileListEntry{
@@ -417,9 +405,9 @@ func findRelatedFiles(dir, videoBaseName, videoPath string) ([]FileListEntry, er
 	})
 
 	// Extract episode number from video file name for matching
-	episodeNum := extractEpisodeNumber(videoBaseName)
-	if episodeNum == "" {
-		log.Printf("⚠️ Could not extract episode number from: %s", videoBaseName)
+	video := parseReleaseName(videoBaseName)
+	if video.episodeID() == "" && video.Date == "" {
+		log.Warnf("⚠️ Could not extract episode number from: %s", videoBaseName)
 		return entries, nil
 	}
 
@@ -443,7 +431,7 @@ func findRelatedFiles(dir, videoBaseName, videoPath string) ([]FileListEntry, er
 		}
 
 		// Check if file is related based on episode number
-		if isRelatedFileByEpisode(fileBaseName, episodeNum) {
+		if isRelatedFileByEpisode(fileBaseName, video) {
 			filePath := filepath.Join(dir, fileName)
 			info, err := entry.Info()
 			if err != nil {
@@ -459,7 +447,7 @@ func findRelatedFiles(dir, videoBaseName, videoPath string) ([]FileListEntry, er
 			relPath = filepath.ToSlash(relPath)
 			if relPath == "." {
 				relPath = filepath.Base(filePath)
-				log.Printf("⚠️ Fixed invalid related file path '.' to '%s' for file: %s (baseDir: %s)", relPath, filePath, baseDir)
+				log.Warnf("⚠️ Fixed invalid related file path '.' to '%s' for file: %s (baseDir: %s)", relPath, filePath, baseDir)
 			}
 
 			entries = append(entries, FileListEntry{
@@ -472,31 +460,49 @@ func findRelatedFiles(dir, videoBaseName, videoPath string) ([]FileListEntry, er
 	return entries, nil
 }
 
-// extractEpisodeNumber extracts episode number from filename (S01E02 or E02)
-func extractEpisodeNumber(fileName string) string {
-	// Pattern to match SxxExx or just Exx (case insensitive)
-	episodePattern := regexp.MustCompile(`(?i)S\d{2,4}(E\d{2,4})|(?i)(E\d{2,4})`)
+// isRelatedFileByEpisode checks if a file is related based on episode number
+// For multi-episode videos (E01E02) files of each covered episode are related, ranges like E01-E02 are expanded
+// Specials (OVA, SP01) match by their token, files of another season (S00E05 next to S01E05) are not related
+// Daily shows match by air date in any supported format (2024-05-17, 2024.05.17, 17.05.2024)
+func isRelatedFileByEpisode(fileName string, video ReleaseInfo) bool {
+	file := parseReleaseName(fileName)
+	if video.Date != "" {
+		return file.Date == video.Date
+	}
+	if file.Special != "" || video.Special != "" {
+		return strings.EqualFold(file.Special, video.Special)
+	}
+	if file.seasonToken != "" && video.seasonToken != "" && file.Season != video.Season {
+		return false
+	}
 
-	matches := episodePattern.FindStringSubmatch(fileName)
-	if len(matches) > 0 {
-		// Return the episode part (E01, E02, etc.)
-		for i := 1; i < len(matches); i++ {
-			if matches[i] != "" && strings.HasPrefix(strings.ToUpper(matches[i]), "E") {
-				return strings.ToUpper(matches[i])
-			}
-		}
+	fileEpisodes, videoEpisodes := file.Episodes, video.Episodes
+	if file.Absolute > 0 {
+		fileEpisodes = []int{file.Absolute}
+	}
+	if video.Absolute > 0 {
+		videoEpisodes = []int{video.Absolute}
+	}
+	if len(fileEpisodes) == 0 {
+		return false
 	}
 
-	return ""
+	for _, episode := range fileEpisodes {
+		if !containsInt(videoEpisodes, episode) {
+			return false
+		}
+	}
+	return true
 }
 
-// isRelatedFileByEpisode checks if a file is related based on episode number
-func isRelatedFileByEpisode(fileName, episodeNum string) bool {
-	// Extract episode number from the file name
-	fileEpisodeNum := extractEpisodeNumber(fileName)
-
-	// Compare episode numbers (case insensitive)
-	return strings.EqualFold(fileEpisodeNum, episodeNum)
+// containsInt checks if the list contains the value
+func containsInt(list []int, value int) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
 }
 
 // countVideoFilesInDirectory counts video files in the main directory (not subdirectories)
@@ -547,11 +553,11 @@ func findGeneralNFO(dir string) string {
 			continue
 		}
 
-		name := strings.ToLower(entry.Name())
-		if strings.HasSuffix(name, ".nfo") {
-			// Check if it's not an episode-specific NFO
-			if !regexp.MustCompile(`s\d{2,4}e\d{2,4}`).MatchString(name) {
-				return filepath.Join(dir, entry.Name())
+		name := entry.Name()
+		if strings.HasSuffix(strings.ToLower(name), ".nfo") {
+			// Check if it's not an episode-specific NFO (SxxExx, air date, absolute number or special)
+			if !parseReleaseName(strings.TrimSuffix(name, filepath.Ext(name))).IsEpisode() {
+				return filepath.Join(dir, name)
 			}
 		}
 	}
@@ -560,7 +566,8 @@ func findGeneralNFO(dir string) string {
 }
 
 // extractEpisodeInfo extracts episode information from video file path
-func extractEpisodeInfo(videoFile VideoFile, seasonPackName, generalNFO string) EpisodeInfo {
+// inSeasonDir is set if the video lies directly in the season (pack) folder rather than in its own episode folder
+func extractEpisodeInfo(log *Logger, videoFile VideoFile, seasonPackName string, inSeasonDir bool, generalNFO string) EpisodeInfo {
 	episodeInfo := EpisodeInfo{
 		VideoFile: videoFile,
 	}
@@ -568,21 +575,26 @@ func extractEpisodeInfo(videoFile VideoFile, seasonPackName, generalNFO string)
 	// Check if video is in subdirectory (episode folder structure)
 	parentDir := filepath.Base(videoFile.Dir)
 
-	// Pattern to match episode numbers SxxExx
-	episodePattern := regexp.MustCompile(`(?i)S(\d{2,4})E(\d{2,4})`)
-
-	// Pattern to match ISO date format (yyyy-mm-dd)
-	isoDatePattern := regexp.MustCompile(`(\d{4}-\d{2}-\d{2})`)
-
-	// Check if video is in a subdirectory by comparing parent directory with season pack name
-	// If parentDir matches seasonPackName, then videos are in main directory
-	if strings.EqualFold(parentDir, seasonPackName) {
+	// Videos in the season folder are identified by their file names, otherwise by their episode folder
+	if inSeasonDir {
 		// Videos are in main directory - analyze filename
 		fileName := strings.TrimSuffix(videoFile.Name, filepath.Ext(videoFile.Name))
 
+		info := parseReleaseName(fileName)
+
 		// Try SxxExx pattern first
-		if matches := episodePattern.FindStringSubmatch(fileName); len(matches) > 0 {
-			episodeInfo.EpisodeNum = "E" + matches[2]
+		if info.seasonToken != "" && len(info.Episodes) > 0 {
+			episodeInfo.EpisodeNum = info.episodeID()
+			episodeInfo.Episodes = info.Episodes
+			episodeInfo.Special = info.IsSpecial()
+			if len(info.Episodes) > 1 {
+				log.Debugf("   Multi-episode file covers %s", episodeInfo.EpisodeNum)
+			}
+
+			// Specials (S00E05) in a season pack are named after season 0
+			if pack := parseReleaseName(seasonPackName); pack.seasonToken != "" && !pack.IsMultiSeason() && pack.Season != info.Season {
+				seasonPackName = seasonPackNameFor(seasonPackName, info.Season)
+			}
 
 			// Check if filename matches season pack prefix AND is not completely lowercase
 			if isValidEpisodeFileName(fileName, seasonPackName) && !isCompletelyLowercase(fileName) {
@@ -600,37 +612,69 @@ func extractEpisodeInfo(videoFile VideoFile, seasonPackName, generalNFO string)
 				episodeInfo.ReleaseName = generateEpisodeReleaseName(seasonPackName, episodeInfo.EpisodeNum)
 			}
 
-			// If no episode-specific NFO found and this is E01, use general NFO
-			if episodeInfo.NFOFile == "" && episodeInfo.EpisodeNum == "E01" && generalNFO != "" {
+			// If no episode-specific NFO found and this is E01 (or a multi-episode file starting with it), use general NFO
+			if episodeInfo.NFOFile == "" && episodeInfo.Episodes[0] == 1 && generalNFO != "" {
 				episodeInfo.NFOFile = generalNFO
 			}
-		} else if matches := isoDatePattern.FindStringSubmatch(fileName); len(matches) > 0 {
-			// Handle ISO date format
-			episodeInfo.EpisodeNum = matches[1] // Use the full date as episode identifier
+		} else if info.Date != "" {
+			// Handle daily shows, the date is normalized to yyyy-mm-dd
+			episodeInfo.EpisodeNum = info.Date // Use the full date as episode identifier
 			episodeInfo.ReleaseName = fileName
 
-			// Look for NFO with same name
+			// Look for NFO with same name, otherwise for one with the same air date
 			nfoPath := filepath.Join(videoFile.Dir, fileName+".nfo")
 			if _, err := os.Stat(nfoPath); err == nil {
 				episodeInfo.NFOFile = nfoPath
+			} else {
+				episodeInfo.NFOFile = findNFOByDate(videoFile.Dir, info.Date)
 			}
 
 			// If no episode-specific NFO found, use general NFO
 			if episodeInfo.NFOFile == "" && generalNFO != "" {
 				episodeInfo.NFOFile = generalNFO
 			}
+		} else if info.IsEpisode() {
+			// Anime absolute numbering (Show - 137, Show.E137) or specials without season (OVA, SP01)
+			episodeInfo.EpisodeNum = info.episodeID()
+			episodeInfo.Episodes = info.Episodes
+			episodeInfo.Special = info.IsSpecial()
+
+			if strings.ContainsRune(seasonPackName, ' ') && !isCompletelyLowercase(fileName) {
+				// Fansub packs aren't scene releases, their files already carry the episode name
+				episodeInfo.ReleaseName = fileName
+			} else {
+				episodeInfo.ReleaseName = generateAbsoluteReleaseName(seasonPackName, episodeInfo.EpisodeNum)
+			}
+
+			// Look for NFO with same name
+			nfoPath := filepath.Join(videoFile.Dir, fileName+".nfo")
+			if _, err := os.Stat(nfoPath); err == nil {
+				episodeInfo.NFOFile = nfoPath
+			}
+
+			// If no episode-specific NFO found and this is the first episode, use general NFO
+			if episodeInfo.NFOFile == "" && info.isFirstEpisode() && generalNFO != "" {
+				episodeInfo.NFOFile = generalNFO
+			}
 		}
 	} else {
 		// Video is in subdirectory - use directory name as release name
+		info := parseReleaseName(parentDir)
+
 		// Try SxxExx pattern first
-		if matches := episodePattern.FindStringSubmatch(parentDir); len(matches) > 0 {
-			episodeInfo.EpisodeNum = "E" + matches[2]
+		if info.seasonToken != "" && len(info.Episodes) > 0 {
+			episodeInfo.EpisodeNum = info.episodeID()
+			episodeInfo.Episodes = info.Episodes
+			episodeInfo.Special = info.IsSpecial()
+			if len(info.Episodes) > 1 {
+				log.Debugf("   Multi-episode file covers %s", episodeInfo.EpisodeNum)
+			}
 
 			// For subdirectory names, check if they match season pack prefix
 			if isValidEpisodeFileName(parentDir, seasonPackName) {
 				// Normal release name - reject if completely lowercase
 				if isCompletelyLowercase(parentDir) {
-					log.Printf("⚠️ Rejecting lowercase normal release name: %s", parentDir)
+					log.Warnf("⚠️ Rejecting lowercase normal release name: %s", parentDir)
 					return episodeInfo // Return empty episodeInfo
 				}
 			}
@@ -640,13 +684,13 @@ func extractEpisodeInfo(videoFile VideoFile, seasonPackName, generalNFO string)
 			// Look for NFO in the same directory
 			episodeInfo.NFOFile = findNFOInDirectory(videoFile.Dir)
 
-			// If no episode-specific NFO found and this is E01, use general NFO
-			if episodeInfo.NFOFile == "" && episodeInfo.EpisodeNum == "E01" && generalNFO != "" {
+			// If no episode-specific NFO found and this is E01 (or a multi-episode file starting with it), use general NFO
+			if episodeInfo.NFOFile == "" && episodeInfo.Episodes[0] == 1 && generalNFO != "" {
 				episodeInfo.NFOFile = generalNFO
 			}
-		} else if matches := isoDatePattern.FindStringSubmatch(parentDir); len(matches) > 0 {
-			// Handle ISO date format in subdirectory
-			episodeInfo.EpisodeNum = matches[1] // Use the full date as episode identifier
+		} else if info.Date != "" {
+			// Handle daily shows in subdirectory
+			episodeInfo.EpisodeNum = info.Date // Use the full date as episode identifier
 			episodeInfo.ReleaseName = parentDir
 
 			// Look for NFO in the same directory
@@ -656,6 +700,20 @@ func extractEpisodeInfo(videoFile VideoFile, seasonPackName, generalNFO string)
 			if episodeInfo.NFOFile == "" && generalNFO != "" {
 				episodeInfo.NFOFile = generalNFO
 			}
+		} else if info.IsEpisode() {
+			// Anime absolute numbering or specials without season in subdirectory
+			episodeInfo.EpisodeNum = info.episodeID()
+			episodeInfo.Episodes = info.Episodes
+			episodeInfo.Special = info.IsSpecial()
+			episodeInfo.ReleaseName = parentDir
+
+			// Look for NFO in the same directory
+			episodeInfo.NFOFile = findNFOInDirectory(videoFile.Dir)
+
+			// If no episode-specific NFO found and this is the first episode, use general NFO
+			if episodeInfo.NFOFile == "" && info.isFirstEpisode() && generalNFO != "" {
+				episodeInfo.NFOFile = generalNFO
+			}
 		}
 	}
 
@@ -676,6 +734,26 @@ func isCompletelyLowercase(s string) bool {
 	return hasLetter // Only return true if there are actually letters
 }
 
+// findNFOByDate finds the NFO of a daily show episode by its air date, regardless of the date format in its name
+func findNFOByDate(dir, date string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(strings.ToLower(name), ".nfo") {
+			continue
+		}
+		if parseReleaseName(strings.TrimSuffix(name, filepath.Ext(name))).Date == date {
+			return filepath.Join(dir, name)
+		}
+	}
+
+	return ""
+}
+
 // findNFOInDirectory finds NFO file in the given directory
 func findNFOInDirectory(dir string) string {
 	entries, err := os.ReadDir(dir)
@@ -700,25 +778,16 @@ func findNFOInDirectory(dir string) string {
 
 // isValidEpisodeFileName validates if episode filename matches season pack naming
 func isValidEpisodeFileName(fileName, seasonPackName string) bool {
-	// Extract prefix before Sxx from season pack name
-	seasonPattern := regexp.MustCompile(`(?i)^(.+?)\.?S\d{2,4}`)
-	seasonMatches := seasonPattern.FindStringSubmatch(seasonPackName)
-	if len(seasonMatches) < 2 {
+	pack := parseReleaseName(seasonPackName)
+	episode := parseReleaseName(fileName)
+	if pack.seasonToken == "" || episode.seasonToken == "" || len(episode.Episodes) == 0 {
 		return false
 	}
-
-	seasonPrefix := normalizeString(seasonMatches[1])
-
-	// Extract prefix before SxxExx from filename
-	episodePattern := regexp.MustCompile(`(?i)^(.+?)\.?S\d{2,4}E\d{2,4}`)
-	episodeMatches := episodePattern.FindStringSubmatch(fileName)
-	if len(episodeMatches) < 2 {
+	if pack.Year != 0 && episode.Year != 0 && pack.Year != episode.Year {
 		return false
 	}
 
-	episodePrefix := normalizeString(episodeMatches[1])
-
-	return seasonPrefix == episodePrefix
+	return normalizeString(pack.Title) == normalizeString(episode.Title)
 }
 
 // normalizeString normalizes string for comparison (removes dots, spaces, case)
@@ -732,17 +801,124 @@ func normalizeString(s string) string {
 // generateEpisodeReleaseName generates release name from season pack name and episode number
 func generateEpisodeReleaseName(seasonPackName, episodeNum string) string {
 	// Remove COMPLETE/iNCOMPLETE from season pack name
-	cleanName := regexp.MustCompile(`(?i)\b(COMPLETE|iNCOMPLETE)\b`).ReplaceAllString(seasonPackName, "")
+	cleanName := regexp.MustCompile(`(?i)[._ ]?\b(COMPLETE|iNCOMPLETE)\b`).ReplaceAllString(seasonPackName, "")
 	cleanName = strings.TrimSpace(cleanName)
 
 	// Replace Sxx with SxxExx
-	seasonPattern := regexp.MustCompile(`(?i)\bS(\d{2,4})\b`)
-	return seasonPattern.ReplaceAllStringFunc(cleanName, func(match string) string {
-		seasonNum := regexp.MustCompile(`\d{2,4}`).FindString(match)
-		return "S" + seasonNum + episodeNum
+	seasonToken := parseReleaseName(cleanName).seasonToken
+	if seasonToken == "" {
+		return cleanName
+	}
+	return replaceSeasonToken(cleanName, seasonToken, strings.ToUpper(seasonToken)+episodeNum)
+}
+
+// replaceSeasonToken replaces the first occurrence of the season token that stands on its own within the name
+func replaceSeasonToken(name, seasonToken, replacement string) string {
+	seasonPattern := regexp.MustCompile(`(^|[._ -])` + regexp.QuoteMeta(seasonToken) + `([._ -]|$)`)
+	replaced := false
+	return seasonPattern.ReplaceAllStringFunc(name, func(match string) string {
+		if replaced {
+			return match
+		}
+		replaced = true
+		return strings.Replace(match, seasonToken, replacement, 1)
 	})
 }
 
+// generateAbsoluteReleaseName generates the release name of an absolute numbered episode or special from the season pack name,
+// the episode takes the place of the season (Show.S01.1080p-GRP becomes Show.E137.1080p-GRP or Show.OVA2.1080p-GRP)
+func generateAbsoluteReleaseName(seasonPackName, episodeID string) string {
+	cleanName := completeSeriesPattern.ReplaceAllString(seasonPackName, "")
+	cleanName = regexp.MustCompile(`(?i)[._ ]?\b(COMPLETE|iNCOMPLETE)\b`).ReplaceAllString(cleanName, "")
+	return withNameToken(strings.TrimSpace(cleanName), episodeID)
+}
+
+// seasonPackNameFor derives the release name of a single season from a multi-season or complete series pack name,
+// e.g. Show.S01-S05.1080p-GRP or Show.Complete.Series.1080p-GRP become Show.S02.1080p-GRP
+func seasonPackNameFor(packName string, season int) string {
+	cleanName := strings.TrimSpace(completeSeriesPattern.ReplaceAllString(packName, ""))
+	return withNameToken(cleanName, fmt.Sprintf("S%02d", season))
+}
+
+// withNameToken replaces the season token of a release name, names without season get the token after title and year
+func withNameToken(name, token string) string {
+	info := parseReleaseName(name)
+	if info.seasonToken != "" {
+		return replaceSeasonToken(name, info.seasonToken, token)
+	}
+	if info.seasonAt == 0 {
+		return name
+	}
+
+	// Use the name's own separator
+	separator := "."
+	if info.seasonAt < len(name) && strings.ContainsRune(" _", rune(name[info.seasonAt])) {
+		separator = name[info.seasonAt : info.seasonAt+1]
+	}
+	return name[:info.seasonAt] + separator + token + name[info.seasonAt:]
+}
+
+// seasonFromFolder returns the season of folders like "Season 1", "Staffel 02" or "S03", "Specials" is season 0
+func seasonFromFolder(name string) (int, bool) {
+	if strings.EqualFold(strings.TrimSpace(name), "specials") {
+		return 0, true
+	}
+	match := seasonFolderPattern.FindStringSubmatch(strings.TrimSpace(name))
+	if match == nil {
+		return 0, false
+	}
+	return atoi(match[1]), true
+}
+
+// episodeSeasonPack determines the season pack name an episode is named after, whether the episode lies directly
+// in that season's folder (file names identify episodes) and which general NFO applies to it
+// Single-season packs keep the pack name, multi-season and complete series packs resolve the season per file or folder
+func episodeSeasonPack(videoFile VideoFile, packDir, packName, generalNFO string) (string, bool, string) {
+	packInfo := parseReleaseName(packName)
+	parentDir := filepath.Base(videoFile.Dir)
+	inPackDir := filepath.Clean(videoFile.Dir) == filepath.Clean(packDir) || strings.EqualFold(parentDir, packName)
+
+	firstSeason := packInfo.Season
+	if firstSeason == 0 {
+		firstSeason = 1
+	}
+	seasonNFO := func(season int, dir string) string {
+		if nfo := findGeneralNFO(dir); nfo != "" && dir != packDir {
+			return nfo
+		}
+		// The NFO of a multi-season pack belongs to its first season
+		if !packInfo.IsMultiSeason() || season == firstSeason {
+			return generalNFO
+		}
+		return ""
+	}
+
+	// Season folders: "Season 2" or a season release like Show.S02.1080p.WEB-GRP
+	if !inPackDir {
+		if season, ok := seasonFromFolder(parentDir); ok {
+			return seasonPackNameFor(packName, season), true, seasonNFO(season, videoFile.Dir)
+		}
+		if folderInfo := parseReleaseName(parentDir); folderInfo.IsSeasonPack() && !folderInfo.IsMultiSeason() && folderInfo.Title != "" {
+			return parentDir, true, seasonNFO(folderInfo.Season, videoFile.Dir)
+		}
+	}
+
+	if !packInfo.IsMultiSeason() {
+		return packName, inPackDir, generalNFO
+	}
+
+	// Episode files or folders directly in a multi-season pack carry their season in the name
+	season := firstSeason
+	name := videoFile.Name
+	if !inPackDir {
+		name = parentDir
+	}
+	if info := parseReleaseName(name); info.seasonToken != "" {
+		season = info.Season
+	}
+	return seasonPackNameFor(packName, season), inPackDir, seasonNFO(season, packDir)
+}
+
 // isHashOnlyFile checks if the file extension is for hash-only files (ISO/IMG)
 func isHashOnlyFile(filePath string) bool {
 	ext := strings.ToLower(filepath.Ext(filePath))
@@ -753,3 +929,67 @@ func isHashOnlyFile(filePath string) bool {
 	}
 	return false
 }
+
+// readJSONFile reads a JSON file into target, leaving target untouched if the file does not exist
+func readJSONFile(path string, target interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	if len(data) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(data, target)
+}
+
+// writeJSONFile writes data as indented JSON via a temporary file so readers never see a partial file
+func writeJSONFile(path string, data interface{}) error {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, jsonData, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+// withFileLock runs fn while holding an exclusive lock file next to path
+// qBittorrent may start several post-processors at once, so shared state files must be guarded
+func withFileLock(path string, fn func() error) error {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(30 * time.Second)
+
+	for {
+		lockFile, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			lockFile.Close()
+			break
+		}
+		if !os.IsExist(err) {
+			return err
+		}
+
+		// Remove stale locks left behind by crashed processes
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > 2*time.Minute {
+			os.Remove(lockPath)
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for lock %s", lockPath)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	defer os.Remove(lockPath)
+
+	return fn()
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FiltersConfig decides which torrents are processed, exclude rules win over include rules
+type FiltersConfig struct {
+	Include []FilterRule `json:"include,omitempty"` // If set, only torrents matching one of these rules are processed
+	Exclude []FilterRule `json:"exclude,omitempty"` // Torrents matching one of these rules are skipped
+}
+
+// FilterRule matches torrents by qBittorrent metadata, all conditions that are set must match
+type FilterRule struct {
+	Name        string   `json:"name,omitempty"`
+	Categories  []string `json:"categories,omitempty"`   // qBittorrent categories (%L), case-insensitive
+	Tags        []string `json:"tags,omitempty"`         // qBittorrent tags (%G), one matching tag is enough
+	Trackers    []string `json:"trackers,omitempty"`     // Tracker hostnames or URLs (%T), subdomains match as well
+	NamePattern string   `json:"name_pattern,omitempty"` // Regex on the torrent name (%N)
+	MinSize     string   `json:"min_size,omitempty"`     // Torrent size (%Z), e.g. "500MB" or "2GB"
+	MaxSize     string   `json:"max_size,omitempty"`
+	MinFiles    int      `json:"min_files,omitempty"` // Number of files (%C)
+	MaxFiles    int      `json:"max_files,omitempty"`
+
+	regex            *regexp.Regexp
+	minSize, maxSize int64
+}
+
+// label identifies the rule in log messages
+func (r FilterRule) label(list string, index int) string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return fmt.Sprintf("%s #%d", list, index+1)
+}
+
+// compileFilterRules validates the rules and precompiles patterns and sizes
+func compileFilterRules(rules []FilterRule) error {
+	for i := range rules {
+		rule := &rules[i]
+		if len(rule.Categories) == 0 && len(rule.Tags) == 0 && len(rule.Trackers) == 0 && rule.NamePattern == "" &&
+			rule.MinSize == "" && rule.MaxSize == "" && rule.MinFiles == 0 && rule.MaxFiles == 0 {
+			return fmt.Errorf("rule %d has no conditions", i+1)
+		}
+
+		if rule.NamePattern != "" {
+			regex, err := regexp.Compile(rule.NamePattern)
+			if err != nil {
+				return fmt.Errorf("rule %d: invalid name_pattern: %v", i+1, err)
+			}
+			rule.regex = regex
+		}
+
+		var err error
+		if rule.MinSize != "" {
+			if rule.minSize, err = parseSizeWithUnit(rule.MinSize); err != nil {
+				return fmt.Errorf("rule %d: invalid min_size: %v", i+1, err)
+			}
+		}
+		if rule.MaxSize != "" {
+			if rule.maxSize, err = parseSizeWithUnit(rule.MaxSize); err != nil {
+				return fmt.Errorf("rule %d: invalid max_size: %v", i+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+// torrentFacts provides size and file count of a torrent, read from disk if qBittorrent didn't pass %Z/%C
+type torrentFacts struct {
+	qbtArgs QBittorrentArgs
+	loaded  bool
+	size    int64
+	files   int
+}
+
+func (f *torrentFacts) load() {
+	if f.loaded {
+		return
+	}
+	f.loaded = true
+
+	size, sizeErr := strconv.ParseInt(strings.TrimSpace(f.qbtArgs.TorrentSize), 10, 64)
+	files, filesErr := strconv.Atoi(strings.TrimSpace(f.qbtArgs.NumberFiles))
+	if sizeErr == nil && filesErr == nil {
+		f.size, f.files = size, files
+		return
+	}
+
+	var diskSize int64
+	paths := listReleaseFiles(f.qbtArgs.ContentPath)
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil {
+			diskSize += info.Size()
+		}
+	}
+	if sizeErr != nil {
+		size = diskSize
+	}
+	if filesErr != nil {
+		files = len(paths)
+	}
+	f.size, f.files = size, files
+}
+
+// match checks all conditions of the rule and describes what matched
+func (r FilterRule) match(qbtArgs QBittorrentArgs, facts *torrentFacts) (bool, string) {
+	var reasons []string
+
+	if len(r.Categories) > 0 {
+		matched := false
+		for _, category := range r.Categories {
+			if strings.EqualFold(strings.TrimSpace(category), strings.TrimSpace(qbtArgs.Category)) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false, ""
+		}
+		reasons = append(reasons, fmt.Sprintf("category '%s'", qbtArgs.Category))
+	}
+
+	if len(r.Tags) > 0 {
+		var matchedTag string
+		for _, tag := range strings.Split(qbtArgs.Tags, ",") {
+			tag = strings.TrimSpace(tag)
+			for _, ruleTag := range r.Tags {
+				if tag != "" && strings.EqualFold(tag, strings.TrimSpace(ruleTag)) {
+					matchedTag = tag
+				}
+			}
+			if matchedTag != "" {
+				break
+			}
+		}
+		if matchedTag == "" {
+			return false, ""
+		}
+		reasons = append(reasons, fmt.Sprintf("tag '%s'", matchedTag))
+	}
+
+	if len(r.Trackers) > 0 {
+		host := trackerHost(qbtArgs.Tracker)
+		matched := false
+		for _, tracker := range r.Trackers {
+			if host != "" && trackerMatches(host, tracker) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false, ""
+		}
+		reasons = append(reasons, fmt.Sprintf("tracker '%s'", host))
+	}
+
+	if r.regex != nil {
+		if !r.regex.MatchString(qbtArgs.TorrentName) {
+			return false, ""
+		}
+		reasons = append(reasons, fmt.Sprintf("name matches '%s'", r.NamePattern))
+	}
+
+	if r.MinSize != "" || r.MaxSize != "" {
+		facts.load()
+		if (r.MinSize != "" && facts.size < r.minSize) || (r.MaxSize != "" && facts.size > r.maxSize) {
+			return false, ""
+		}
+		reasons = append(reasons, fmt.Sprintf("size %s", formatBytes(facts.size)))
+	}
+
+	if r.MinFiles > 0 || r.MaxFiles > 0 {
+		facts.load()
+		if (r.MinFiles > 0 && facts.files < r.MinFiles) || (r.MaxFiles > 0 && facts.files > r.MaxFiles) {
+			return false, ""
+		}
+		reasons = append(reasons, fmt.Sprintf("%d files", facts.files))
+	}
+
+	return true, strings.Join(reasons, ", ")
+}
+
+// shouldProcessTorrent applies excluded_categories and the include/exclude filter rules
+// Returns false and the reason if the torrent must be skipped
+func shouldProcessTorrent(log *Logger, config *Config, qbtArgs QBittorrentArgs) (bool, string) {
+	if isCategoryExcluded(config, qbtArgs.Category) {
+		return false, fmt.Sprintf("category '%s' is in excluded_categories", qbtArgs.Category)
+	}
+
+	facts := &torrentFacts{qbtArgs: qbtArgs}
+
+	for i, rule := range config.Filters.Exclude {
+		if matched, reason := rule.match(qbtArgs, facts); matched {
+			return false, fmt.Sprintf("exclude rule '%s' matched (%s)", rule.label("exclude", i), reason)
+		}
+	}
+
+	if len(config.Filters.Include) == 0 {
+		return true, ""
+	}
+	for i, rule := range config.Filters.Include {
+		if matched, reason := rule.match(qbtArgs, facts); matched {
+			log.Debugf("🔍 Include rule '%s' matched (%s)", rule.label("include", i), reason)
+			return true, ""
+		}
+	}
+	return false, "no include rule matched"
+}
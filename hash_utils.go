@@ -0,0 +1,320 @@
+package main
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	hashBufferSize                 = 1024 * 1024
+	defaultHashProgressIntervalSec = 10
+)
+
+// FileDigests maps an algorithm name (sha256, crc32, xxh64, md5, sha1) to the hex encoded digest of a file
+type FileDigests map[string]string
+
+// newHashers returns sha256, which CrowdNFO always needs, plus the given additional algorithms
+func newHashers(log *Logger, algorithms []string) map[string]hash.Hash {
+	hashers := map[string]hash.Hash{"sha256": sha256.New()}
+
+	for _, algorithm := range algorithms {
+		switch normalizeHashAlgorithm(algorithm) {
+		case "sha256":
+		case "crc32":
+			hashers["crc32"] = crc32.NewIEEE()
+		case "xxh64":
+			hashers["xxh64"] = newXXH64()
+		case "md5":
+			hashers["md5"] = md5.New()
+		case "sha1":
+			hashers["sha1"] = sha1.New()
+		default:
+			log.Warnf("⚠️ Unknown hash algorithm in config: '%s', skipping", algorithm)
+		}
+	}
+
+	return hashers
+}
+
+// normalizeHashAlgorithm maps an algorithm from the config to its digest name, unknown algorithms return an empty string
+func normalizeHashAlgorithm(algorithm string) string {
+	switch strings.ToLower(strings.TrimSpace(algorithm)) {
+	case "sha256":
+		return "sha256"
+	case "crc32":
+		return "crc32"
+	case "xxh64", "xxhash":
+		return "xxh64"
+	case "md5":
+		return "md5"
+	case "sha1":
+		return "sha1"
+	default:
+		return ""
+	}
+}
+
+// hashFile calculates sha256 and the given additional digests of a file in a single read pass
+// Progress and throughput are logged periodically, reading stops when ctx is cancelled or the configured timeout expires
+func hashFile(ctx context.Context, log *Logger, config *Config, filePath string, algorithms []string) (FileDigests, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Hashing.TimeoutSeconds > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(config.Hashing.TimeoutSeconds)*time.Second)
+		defer cancel()
+	}
+
+	hashers := newHashers(log, algorithms)
+	writers := make([]io.Writer, 0, len(hashers))
+	for _, h := range hashers {
+		writers = append(writers, h)
+	}
+	writer := io.MultiWriter(writers...)
+
+	progressInterval := time.Duration(config.Hashing.ProgressInterval) * time.Second
+	if progressInterval <= 0 {
+		progressInterval = defaultHashProgressIntervalSec * time.Second
+	}
+	rateLimit := config.Hashing.RateLimitMBps * 1024 * 1024
+
+	fileName := filepath.Base(filePath)
+	buffer := make([]byte, hashBufferSize)
+	start := time.Now()
+	lastProgress := start
+	var bytesRead int64
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("hashing %s aborted after %s: %w", fileName, formatBytes(bytesRead), err)
+		}
+
+		n, readErr := file.Read(buffer)
+		if n > 0 {
+			writer.Write(buffer[:n])
+			bytesRead += int64(n)
+
+			// Throttle reads so hashing doesn't starve seeding
+			if rateLimit > 0 {
+				expected := time.Duration(float64(bytesRead) / rateLimit * float64(time.Second))
+				if wait := expected - time.Since(start); wait > 0 {
+					select {
+					case <-ctx.Done():
+					case <-time.After(wait):
+					}
+				}
+			}
+
+			if time.Since(lastProgress) >= progressInterval {
+				lastProgress = time.Now()
+				log.Infof("⏳ Hashing %s: %.0f%% (%s / %s, %s/s)", fileName, percentOf(bytesRead, info.Size()), formatBytes(bytesRead), formatBytes(info.Size()), formatBytes(throughput(bytesRead, time.Since(start))))
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, readErr
+		}
+	}
+
+	elapsed := time.Since(start)
+	log.Infof("🔢 Hashed %s (%s in %s, %s/s)", fileName, formatBytes(bytesRead), elapsed.Round(100*time.Millisecond), formatBytes(throughput(bytesRead, elapsed)))
+
+	digests := make(FileDigests, len(hashers))
+	for name, h := range hashers {
+		digests[name] = hex.EncodeToString(h.Sum(nil))
+	}
+	if len(digests) > 1 {
+		log.Debugf("   Digests: %s", digests.String())
+	}
+
+	return digests, nil
+}
+
+// String lists the digests sorted by algorithm name
+func (d FileDigests) String() string {
+	names := make([]string, 0, len(d))
+	for name := range d {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+"="+d[name])
+	}
+	return strings.Join(parts, " ")
+}
+
+func percentOf(part, total int64) float64 {
+	if total <= 0 {
+		return 100
+	}
+	return float64(part) / float64(total) * 100
+}
+
+func throughput(bytes int64, elapsed time.Duration) int64 {
+	if elapsed <= 0 {
+		return bytes
+	}
+	return int64(float64(bytes) / elapsed.Seconds())
+}
+
+// formatBytes formats a byte count for log messages, e.g. 1.5 GB
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// XXH64 (seed 0), see https://github.com/Cyan4973/xxHash/blob/dev/doc/xxhash_spec.md
+const (
+	xxhPrime1 uint64 = 11400714785074694791
+	xxhPrime2 uint64 = 14029467366897019727
+	xxhPrime3 uint64 = 1609587929392839161
+	xxhPrime4 uint64 = 9650029242287828579
+	xxhPrime5 uint64 = 2870177450012600261
+)
+
+type xxh64 struct {
+	v1, v2, v3, v4 uint64
+	total          uint64
+	mem            [32]byte
+	n              int
+}
+
+func newXXH64() *xxh64 {
+	d := &xxh64{}
+	d.Reset()
+	return d
+}
+
+func (d *xxh64) Reset() {
+	// Variables instead of constants, the initial accumulators rely on uint64 wrap-around
+	prime1, prime2 := xxhPrime1, xxhPrime2
+	d.v1 = prime1 + prime2
+	d.v2 = prime2
+	d.v3 = 0
+	d.v4 = -prime1
+	d.total = 0
+	d.n = 0
+}
+
+func (d *xxh64) Size() int      { return 8 }
+func (d *xxh64) BlockSize() int { return 32 }
+
+func (d *xxh64) Write(b []byte) (int, error) {
+	length := len(b)
+	d.total += uint64(length)
+
+	if d.n+len(b) < 32 {
+		d.n += copy(d.mem[d.n:], b)
+		return length, nil
+	}
+
+	if d.n > 0 {
+		c := copy(d.mem[d.n:], b)
+		d.processBlock(d.mem[:])
+		b = b[c:]
+		d.n = 0
+	}
+
+	for len(b) >= 32 {
+		d.processBlock(b[:32])
+		b = b[32:]
+	}
+
+	d.n = copy(d.mem[:], b)
+	return length, nil
+}
+
+func (d *xxh64) processBlock(b []byte) {
+	d.v1 = xxhRound(d.v1, binary.LittleEndian.Uint64(b[0:8]))
+	d.v2 = xxhRound(d.v2, binary.LittleEndian.Uint64(b[8:16]))
+	d.v3 = xxhRound(d.v3, binary.LittleEndian.Uint64(b[16:24]))
+	d.v4 = xxhRound(d.v4, binary.LittleEndian.Uint64(b[24:32]))
+}
+
+func (d *xxh64) Sum64() uint64 {
+	var h uint64
+	if d.total >= 32 {
+		h = bits.RotateLeft64(d.v1, 1) + bits.RotateLeft64(d.v2, 7) + bits.RotateLeft64(d.v3, 12) + bits.RotateLeft64(d.v4, 18)
+		h = xxhMergeRound(h, d.v1)
+		h = xxhMergeRound(h, d.v2)
+		h = xxhMergeRound(h, d.v3)
+		h = xxhMergeRound(h, d.v4)
+	} else {
+		h = xxhPrime5
+	}
+	h += d.total
+
+	b := d.mem[:d.n]
+	for ; len(b) >= 8; b = b[8:] {
+		h ^= xxhRound(0, binary.LittleEndian.Uint64(b[:8]))
+		h = bits.RotateLeft64(h, 27)*xxhPrime1 + xxhPrime4
+	}
+	if len(b) >= 4 {
+		h ^= uint64(binary.LittleEndian.Uint32(b[:4])) * xxhPrime1
+		h = bits.RotateLeft64(h, 23)*xxhPrime2 + xxhPrime3
+		b = b[4:]
+	}
+	for _, c := range b {
+		h ^= uint64(c) * xxhPrime5
+		h = bits.RotateLeft64(h, 11) * xxhPrime1
+	}
+
+	h ^= h >> 33
+	h *= xxhPrime2
+	h ^= h >> 29
+	h *= xxhPrime3
+	h ^= h >> 32
+	return h
+}
+
+func (d *xxh64) Sum(b []byte) []byte {
+	var out [8]byte
+	binary.BigEndian.PutUint64(out[:], d.Sum64())
+	return append(b, out[:]...)
+}
+
+func xxhRound(acc, input uint64) uint64 {
+	acc += input * xxhPrime2
+	acc = bits.RotateLeft64(acc, 31)
+	return acc * xxhPrime1
+}
+
+func xxhMergeRound(acc, val uint64) uint64 {
+	acc ^= xxhRound(0, val)
+	return acc*xxhPrime1 + xxhPrime4
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestXXH64(t *testing.T) {
+	tests := []struct {
+		input string
+		want  uint64
+	}{
+		{"", 0xef46db3751d8e999},
+		{"a", 0xd24ec4f1a98c6e5b},
+		{"as", 0x1c330fb2d66be179},
+		{"asd", 0x631c37ce72a97393},
+		{"asdf", 0x415872f599cea71e},
+		{"The quick brown fox jumps over the lazy dog", 0x0b242d361fda71bc},
+		{"Call me Ishmael. Some years ago--never mind how long precisely-", 0x02a2e85470d6fd96},
+	}
+
+	for _, tt := range tests {
+		d := newXXH64()
+		d.Write([]byte(tt.input))
+		if got := d.Sum64(); got != tt.want {
+			t.Errorf("xxh64(%q) = %016x, want %016x", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestXXH64ChunkedWrites(t *testing.T) {
+	// Hashing streams the file in chunks, split points must not change the digest
+	input := []byte(strings.Repeat("0123456789abcdef", 20) + "tail")
+
+	whole := newXXH64()
+	whole.Write(input)
+
+	for _, size := range []int{1, 7, 31, 32, 33, 100} {
+		d := newXXH64()
+		for b := input; len(b) > 0; {
+			n := size
+			if n > len(b) {
+				n = len(b)
+			}
+			d.Write(b[:n])
+			b = b[n:]
+		}
+		if got, want := d.Sum64(), whole.Sum64(); got != want {
+			t.Errorf("chunk size %d: xxh64 = %016x, want %016x", size, got, want)
+		}
+	}
+}
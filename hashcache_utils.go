@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// HashCache stores digests of previously hashed files, keyed by absolute path
+type HashCache struct {
+	Entries map[string]HashCacheEntry `json:"entries"`
+}
+
+// HashCacheEntry is only valid while size, mtime and inode of the file are unchanged
+type HashCacheEntry struct {
+	Size     int64       `json:"size"`
+	ModTime  time.Time   `json:"mod_time"`
+	Inode    uint64      `json:"inode,omitempty"`
+	Digests  FileDigests `json:"digests"`
+	CachedAt time.Time   `json:"cached_at"`
+}
+
+// getHashCachePath returns the location of the hash cache next to the config file
+func getHashCachePath() string {
+	return filepath.Join(getCurrentDir(), "crowdclient-hashcache.json")
+}
+
+// matches reports whether the entry still describes the given file
+func (e HashCacheEntry) matches(info os.FileInfo) bool {
+	return e.Size == info.Size() && e.ModTime.Equal(info.ModTime()) && e.Inode == fileInode(info)
+}
+
+// hasDigests reports whether the entry contains sha256 and all given algorithms
+func (e HashCacheEntry) hasDigests(algorithms []string) bool {
+	if e.Digests["sha256"] == "" {
+		return false
+	}
+	for _, algorithm := range algorithms {
+		if name := normalizeHashAlgorithm(algorithm); name != "" && e.Digests[name] == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// cachedHashFile returns the digests of a file from the hash cache, hashing and caching it if necessary
+func cachedHashFile(ctx context.Context, log *Logger, config *Config, filePath string, algorithms []string) (FileDigests, error) {
+	if !config.Hashing.Cache {
+		return hashFile(ctx, log, config, filePath, algorithms)
+	}
+
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cache := &HashCache{}
+	if err := readJSONFile(getHashCachePath(), cache); err != nil {
+		log.Warnf("⚠️ Failed to read hash cache: %v", err)
+	} else if entry, ok := cache.Entries[absPath]; ok && entry.matches(info) && entry.hasDigests(algorithms) {
+		log.Infof("⚡ Using cached hash for %s", filepath.Base(filePath))
+		return entry.Digests, nil
+	}
+
+	digests, err := hashFile(ctx, log, config, absPath, algorithms)
+	if err != nil {
+		return nil, err
+	}
+
+	// Dry-run mode leaves all state files untouched
+	if !config.DryRun {
+		storeHashCacheEntry(log, absPath, HashCacheEntry{
+			Size:     info.Size(),
+			ModTime:  info.ModTime(),
+			Inode:    fileInode(info),
+			Digests:  digests,
+			CachedAt: time.Now(),
+		})
+	}
+
+	return digests, nil
+}
+
+// storeHashCacheEntry adds or replaces the cache entry of a file
+func storeHashCacheEntry(log *Logger, absPath string, entry HashCacheEntry) {
+	cachePath := getHashCachePath()
+	err := withFileLock(cachePath, func() error {
+		cache := &HashCache{}
+		if err := readJSONFile(cachePath, cache); err != nil {
+			return fmt.Errorf("failed to read hash cache: %v", err)
+		}
+		if cache.Entries == nil {
+			cache.Entries = make(map[string]HashCacheEntry)
+		}
+
+		cache.Entries[absPath] = entry
+		return writeJSONFile(cachePath, cache)
+	})
+	if err != nil {
+		log.Warnf("⚠️ Failed to update hash cache: %v", err)
+	}
+}
+
+// pruneHashCache removes entries of files that were deleted or changed since they were hashed
+func pruneHashCache(config *Config) error {
+	cachePath := getHashCachePath()
+	removed, kept := 0, 0
+
+	err := withFileLock(cachePath, func() error {
+		cache := &HashCache{}
+		if err := readJSONFile(cachePath, cache); err != nil {
+			return fmt.Errorf("failed to read hash cache: %v", err)
+		}
+
+		for path, entry := range cache.Entries {
+			// Only files that are gone or changed are stale, an unmounted drive or missing permissions are no reason to drop an entry
+			info, err := os.Stat(path)
+			if err != nil && !os.IsNotExist(err) {
+				logDebugf("   Keeping entry, file not accessible: %s (%v)", path, err)
+				kept++
+				continue
+			}
+			if err != nil || !entry.matches(info) {
+				logDebugf("   Removing stale entry: %s", path)
+				delete(cache.Entries, path)
+				removed++
+				continue
+			}
+			kept++
+		}
+
+		if config.DryRun {
+			logInfof("🧪 DRY RUN: Hash cache not modified")
+			return nil
+		}
+		return writeJSONFile(cachePath, cache)
+	})
+	if err != nil {
+		return err
+	}
+
+	logInfof("✅ Hash cache pruned: %d stale entries removed, %d kept", removed, kept)
+	return nil
+}
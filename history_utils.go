@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// UploadHistory records every upload CrowdNFO (or another uploader) accepted, so re-checked torrents are not uploaded twice
+type UploadHistory struct {
+	Entries map[string]HistoryEntry `json:"entries"`
+}
+
+// HistoryEntry is a single completed upload
+type HistoryEntry struct {
+	Target      string    `json:"target,omitempty"` // Uploader name, empty for CrowdNFO
+	ReleaseName string    `json:"release_name"`
+	FileType    string    `json:"file_type"`
+	Hash        string    `json:"hash,omitempty"`
+	UploadedAt  time.Time `json:"uploaded_at"`
+}
+
+// getHistoryPath returns the location of the upload history next to the config file
+func getHistoryPath() string {
+	return filepath.Join(getCurrentDir(), "crowdclient-history.json")
+}
+
+// historyKey identifies an upload by target, release name, file type and hash
+// CrowdNFO uploads have no target prefix, so histories written before uploaders existed stay valid
+func historyKey(target, releaseName, fileType, hash string) string {
+	key := strings.ToLower(releaseName) + "|" + fileType + "|" + strings.ToLower(hash)
+	if target != "" {
+		key = target + "|" + key
+	}
+	return key
+}
+
+// isAlreadyUploaded checks the local history for a previous upload of the same file
+func isAlreadyUploaded(log *Logger, target, releaseName, fileType, hash string) bool {
+	history := &UploadHistory{}
+	if err := readJSONFile(getHistoryPath(), history); err != nil {
+		log.Warnf("⚠️ Failed to read upload history: %v", err)
+		return false
+	}
+
+	_, ok := history.Entries[historyKey(target, releaseName, fileType, hash)]
+	return ok
+}
+
+// recordUpload adds a completed upload to the local history
+func recordUpload(log *Logger, config *Config, target, releaseName, fileType, hash string) {
+	if config.DryRun {
+		return
+	}
+
+	historyPath := getHistoryPath()
+	err := withFileLock(historyPath, func() error {
+		history := &UploadHistory{}
+		if err := readJSONFile(historyPath, history); err != nil {
+			return fmt.Errorf("failed to read upload history: %v", err)
+		}
+		if history.Entries == nil {
+			history.Entries = make(map[string]HistoryEntry)
+		}
+
+		history.Entries[historyKey(target, releaseName, fileType, hash)] = HistoryEntry{
+			Target:      target,
+			ReleaseName: releaseName,
+			FileType:    fileType,
+			Hash:        hash,
+			UploadedAt:  time.Now(),
+		}
+		return writeJSONFile(historyPath, history)
+	})
+	if err != nil {
+		log.Warnf("⚠️ Failed to update upload history: %v", err)
+	}
+}
@@ -0,0 +1,8 @@
+package main
+
+import "os"
+
+// fileInode is not available via os.FileInfo on Windows, size and mtime are used alone
+func fileInode(info os.FileInfo) uint64 {
+	return 0
+}
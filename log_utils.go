@@ -0,0 +1,330 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+type LogLevel int
+
+const (
+	LevelDebug LogLevel = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+const (
+	defaultLogFile       = "crowdclient.log"
+	defaultLogMaxSizeMB  = 10
+	defaultLogMaxBackups = 5
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return "info"
+	}
+}
+
+// parseLogLevel converts a config value to a log level, defaulting to info
+func parseLogLevel(level string) (LogLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return LevelDebug, nil
+	case "", "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	default:
+		return LevelInfo, fmt.Errorf("unknown log level '%s'", level)
+	}
+}
+
+// logEntry is the JSON line representation of a log message
+type logEntry struct {
+	Time          string `json:"time"`
+	Level         string `json:"level"`
+	CorrelationID string `json:"correlation_id,omitempty"`
+	Message       string `json:"msg"`
+}
+
+// Logger writes leveled messages to the console and optionally to a rotating log file
+// A buffered logger keeps its lines until they are flushed, see newBuffer
+// A child logger passes its lines on to its parent, see withCorrelationID
+type Logger struct {
+	mu            sync.Mutex
+	level         LogLevel
+	jsonFormat    bool
+	console       io.Writer
+	file          *rotatingFile
+	correlationID string
+	parent        *Logger
+	buffered      bool
+	lines         []string
+}
+
+// logger is the process wide logger, configured by initLogging once the config is loaded
+var logger = &Logger{level: LevelInfo, console: os.Stderr}
+
+// initLogging applies the logging section of the config
+func initLogging(config *Config) error {
+	level, err := parseLogLevel(config.Logging.Level)
+
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+
+	logger.level = level
+	logger.jsonFormat = strings.EqualFold(config.Logging.Format, "json")
+
+	if config.Logging.File != "" && logger.file == nil {
+		logPath := config.Logging.File
+		if !filepath.IsAbs(logPath) {
+			logPath = filepath.Join(getCurrentDir(), logPath)
+		}
+
+		maxSizeMB := config.Logging.MaxSizeMB
+		if maxSizeMB <= 0 {
+			maxSizeMB = defaultLogMaxSizeMB
+		}
+		maxBackups := config.Logging.MaxBackups
+		if maxBackups < 0 {
+			maxBackups = 0
+		}
+
+		file, fileErr := openRotatingFile(logPath, int64(maxSizeMB)*1024*1024, maxBackups)
+		if fileErr != nil {
+			return fmt.Errorf("failed to open log file: %v", fileErr)
+		}
+		logger.file = file
+	}
+
+	return err
+}
+
+// closeLogging flushes and closes the log file
+func closeLogging() {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+
+	if logger.file != nil {
+		logger.file.Close()
+		logger.file = nil
+	}
+}
+
+// withCorrelationID returns a logger that tags its messages with id and writes through l
+// Every processed torrent gets its own, so torrents processed in parallel don't mix up their IDs
+func (l *Logger) withCorrelationID(id string) *Logger {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return &Logger{level: l.level, jsonFormat: l.jsonFormat, correlationID: id, parent: l}
+}
+
+// log formats and writes a single message if its level is enabled
+func (l *Logger) log(level LogLevel, format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if level < l.level {
+		return
+	}
+
+	message := fmt.Sprintf(format, args...)
+	now := time.Now()
+
+	var line string
+	if l.jsonFormat {
+		data, err := json.Marshal(logEntry{
+			Time:          now.Format(time.RFC3339),
+			Level:         level.String(),
+			CorrelationID: l.correlationID,
+			Message:       message,
+		})
+		if err != nil {
+			return
+		}
+		line = string(data) + "\n"
+	} else {
+		correlation := ""
+		if l.correlationID != "" {
+			correlation = "[" + l.correlationID + "] "
+		}
+		line = fmt.Sprintf("%s %-5s %s%s\n", now.Format("2006-01-02 15:04:05"), strings.ToUpper(level.String()), correlation, message)
+	}
+
+	l.write(line)
+}
+
+// write outputs a formatted line, the caller must hold l.mu
+func (l *Logger) write(line string) {
+	if l.buffered {
+		l.lines = append(l.lines, line)
+		return
+	}
+
+	if l.parent != nil {
+		l.parent.mu.Lock()
+		l.parent.write(line)
+		l.parent.mu.Unlock()
+		return
+	}
+
+	if l.console != nil {
+		io.WriteString(l.console, line)
+	}
+	if l.file != nil {
+		if err := l.file.Write([]byte(line)); err != nil && l.console != nil {
+			fmt.Fprintf(l.console, "failed to write log file: %v\n", err)
+		}
+	}
+}
+
+// newBuffer returns a logger with the same settings that collects its output until flush is called
+// Used to keep the output of work running in parallel together, e.g. per episode of a season pack
+func (l *Logger) newBuffer() *Logger {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return &Logger{level: l.level, jsonFormat: l.jsonFormat, correlationID: l.correlationID, buffered: true}
+}
+
+// flush writes all lines collected by a buffered logger
+func (l *Logger) flush(buffer *Logger) {
+	buffer.mu.Lock()
+	lines := buffer.lines
+	buffer.lines = nil
+	buffer.mu.Unlock()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, line := range lines {
+		l.write(line)
+	}
+}
+
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	l.log(LevelDebug, format, args...)
+}
+
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.log(LevelInfo, format, args...)
+}
+
+func (l *Logger) Warnf(format string, args ...interface{}) {
+	l.log(LevelWarn, format, args...)
+}
+
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.log(LevelError, format, args...)
+}
+
+func logDebugf(format string, args ...interface{}) {
+	logger.Debugf(format, args...)
+}
+
+func logInfof(format string, args ...interface{}) {
+	logger.Infof(format, args...)
+}
+
+func logWarnf(format string, args ...interface{}) {
+	logger.Warnf(format, args...)
+}
+
+func logErrorf(format string, args ...interface{}) {
+	logger.Errorf(format, args...)
+}
+
+// logFatalf logs an error and exits, closing the log file first
+func logFatalf(format string, args ...interface{}) {
+	logger.log(LevelError, format, args...)
+	closeLogging()
+	os.Exit(1)
+}
+
+// rotatingFile is an append-only log file that is rotated once it exceeds maxSize
+type rotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+func openRotatingFile(path string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	r := &rotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	r.file = file
+	r.size = info.Size()
+	return nil
+}
+
+func (r *rotatingFile) Write(data []byte) error {
+	if r.size+int64(len(data)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := r.file.Write(data)
+	r.size += int64(n)
+	return err
+}
+
+// rotate renames crowdclient.log to crowdclient.log.1, shifting older backups up and dropping the oldest
+func (r *rotatingFile) rotate() error {
+	r.file.Close()
+
+	// Another process may have rotated the file already
+	if info, err := os.Stat(r.path); err == nil && info.Size() < r.size {
+		return r.open()
+	}
+
+	if r.maxBackups == 0 {
+		os.Remove(r.path)
+	} else {
+		os.Remove(fmt.Sprintf("%s.%d", r.path, r.maxBackups))
+		for i := r.maxBackups - 1; i >= 1; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+		}
+		os.Rename(r.path, r.path+".1")
+	}
+
+	return r.open()
+}
+
+func (r *rotatingFile) Close() error {
+	return r.file.Close()
+}
@@ -1,13 +1,16 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
-	"regexp"
 	"strings"
+	"sync"
+	"syscall"
 )
 
 // Version information - set at build time
@@ -19,22 +22,26 @@ var (
 
 // QBittorrentArgs holds the arguments passed from qBittorrent
 type QBittorrentArgs struct {
-	TorrentName  string // %N - Torrent name
-	ContentPath  string // %F - Content path (path of the torrent files)
-	Category     string // %L - Category
-	InfoHash     string // %I - Info hash v1
-	SavePath     string // %D - Save path (same as root path for single file torrents)
-	Tags         string // %G - Tags (empty if untagged)
-	InfoHashV2   string // %J - Info hash v2 (empty if not available)
-	TorrentID    string // %K - Torrent ID
-	RootPath     string // %R - Root path (first torrent subdirectory path)
-	Tracker      string // %T - Tracker
-	TorrentSize  string // %Z - Torrent size (bytes)
-	NumberFiles  string // %C - Number of files
+	TorrentName string `json:"torrent_name"` // %N - Torrent name
+	ContentPath string `json:"content_path"` // %F - Content path (path of the torrent files)
+	Category    string `json:"category"`     // %L - Category
+	InfoHash    string `json:"info_hash"`    // %I - Info hash v1
+	SavePath    string `json:"save_path"`    // %D - Save path (same as root path for single file torrents)
+	Tags        string `json:"tags"`         // %G - Tags (empty if untagged)
+	InfoHashV2  string `json:"info_hash_v2"` // %J - Info hash v2 (empty if not available)
+	TorrentID   string `json:"torrent_id"`   // %K - Torrent ID
+	RootPath    string `json:"root_path"`    // %R - Root path (first torrent subdirectory path)
+	Tracker     string `json:"tracker"`      // %T - Tracker
+	TorrentSize string `json:"torrent_size"` // %Z - Torrent size (bytes)
+	NumberFiles string `json:"number_files"` // %C - Number of files
 }
 
-// Global variable to track update availability
+// dryRunFlag is set by --dry-run and forces config.DryRun
+var dryRunFlag = false
+
+// Global variable to track update availability, guarded by updateMu as uploads may run in parallel
 var (
+	updateMu        sync.Mutex
 	updateAvailable = false
 	latestVersion   = ""
 	updateCheckDone = false
@@ -81,7 +88,18 @@ func getUserAgent() string {
 }
 
 func main() {
-	log.SetFlags(0)
+	defer closeLogging()
+
+	// Strip --dry-run so it can be combined with every mode and the positional arguments
+	args := make([]string, 0, len(os.Args))
+	for _, arg := range os.Args {
+		if arg == "--dry-run" {
+			dryRunFlag = true
+			continue
+		}
+		args = append(args, arg)
+	}
+	os.Args = args
 
 	// Check for version flag
 	if len(os.Args) > 1 && (os.Args[1] == "--version" || os.Args[1] == "-v") {
@@ -91,97 +109,237 @@ func main() {
 		return
 	}
 
-	if len(os.Args) < 13 {
-		log.Fatal("Insufficient arguments. Expected 12 arguments from qBittorrent: torrent_name content_path category info_hash save_path tags info_hash_v2 torrent_id root_path tracker torrent_size number_files")
-	}
-
-	// Parse qBittorrent arguments
-	cleanJobName := os.Args[1]  // %N - Torrent name
-	finalDir := os.Args[2]      // %F - Content path
-	qbtCategory := os.Args[3]   // %L - Category
-	infoHash := os.Args[4]      // %I - Info hash v1
-	savePath := os.Args[5]      // %D - Save path
-	tags := os.Args[6]          // %G - Tags
-	infoHashV2 := os.Args[7]    // %J - Info hash v2
-	torrentID := os.Args[8]     // %K - Torrent ID
-	rootPath := os.Args[9]      // %R - Root path
-	tracker := os.Args[10]      // %T - Tracker
-	torrentSize := os.Args[11]  // %Z - Torrent size
-	numberFiles := os.Args[12]  // %C - Number of files
-
-	// Store qBittorrent arguments for post-processing
-	qbtArgs := QBittorrentArgs{
-		TorrentName:  cleanJobName,
-		ContentPath:  finalDir,
-		Category:     qbtCategory,
-		InfoHash:     infoHash,
-		SavePath:     savePath,
-		Tags:         tags,
-		InfoHashV2:   infoHashV2,
-		TorrentID:    torrentID,
-		RootPath:     rootPath,
-		Tracker:      tracker,
-		TorrentSize:  torrentSize,
-		NumberFiles:  numberFiles,
+	if len(os.Args) > 1 && (os.Args[1] == "--help" || os.Args[1] == "-h") {
+		printUsage(os.Stdout)
+		return
+	}
+
+	// Replay failed uploads from the persistent queue
+	if len(os.Args) > 1 && os.Args[1] == "--retry-queue" {
+		config, err := loadConfig()
+		if err != nil {
+			logFatalf("❌ Failed to load configuration: %v", err)
+		}
+		if err := retryUploadQueue(config); err != nil {
+			logFatalf("❌ Failed to process upload queue: %v", err)
+		}
+		displayUpdateNotification()
+		return
+	}
+
+	// Remove hash cache entries of deleted or changed files
+	if len(os.Args) > 1 && os.Args[1] == "--prune-hash-cache" {
+		config, err := loadConfig()
+		if err != nil {
+			logFatalf("❌ Failed to load configuration: %v", err)
+		}
+		if err := pruneHashCache(config); err != nil {
+			logFatalf("❌ Failed to prune hash cache: %v", err)
+		}
+		return
+	}
+
+	// Run as long-running daemon polling the qBittorrent WebUI API
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		runServe(os.Args[2:])
+		return
+	}
+
+	// Process an existing library directory
+	if len(os.Args) > 1 && os.Args[1] == "backfill" {
+		runBackfill(os.Args[2:])
+		return
+	}
+
+	// Parse qBittorrent arguments (positional or named flags)
+	qbtArgs, err := parseQBittorrentArgs(os.Args[1:])
+	if err != nil {
+		printUsage(os.Stderr)
+		logFatalf("❌ Invalid arguments: %v", err)
 	}
 
 	// Load configuration first
 	config, err := loadConfig()
 	if err != nil {
-		log.Fatal("❌ Failed to load configuration: ", err)
+		logFatalf("❌ Failed to load configuration: %v", err)
 	}
 
-	// Check if category should be excluded from processing
-	if isCategoryExcluded(config, qbtCategory) {
-		log.Printf("ℹ️ Category '%s' is excluded from processing, skipping CrowdNFO upload", qbtCategory)
-		
-		// Execute post-processing commands even if category is excluded
-		executePostProcessing(config, qbtArgs)
-		return
+	if err := validateQBittorrentArgs(config, qbtArgs); err != nil {
+		printUsage(os.Stderr)
+		logFatalf("❌ Invalid arguments: %v", err)
+	}
+
+	// Abort running hash calculations on SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := processTorrent(ctx, config, qbtArgs); err != nil {
+		logFatalf("❌ Processing interrupted: %v", err)
+	}
+}
+
+// processTorrent runs the CrowdNFO upload and post-processing for a single torrent
+// An error is only returned if ctx was cancelled, the torrent must not be treated as processed then
+func processTorrent(ctx context.Context, config *Config, qbtArgs QBittorrentArgs) error {
+	// Tag all messages of this torrent with its info hash, backfill releases have none and use their name
+	correlationID := qbtArgs.InfoHash
+	if correlationID == "" {
+		correlationID = qbtArgs.TorrentID
+	}
+	if correlationID == "" {
+		correlationID = qbtArgs.TorrentName
+	}
+	log := logger.withCorrelationID(correlationID)
+
+	cleanJobName := qbtArgs.TorrentName
+	finalDir := qbtArgs.ContentPath
+
+	// Collect an audit trail of this run, written to archive/<release>/report.json
+	report := newRunReport(config, qbtArgs)
+
+	var archiveDir string
+	defer func() {
+		if archiveDir != "" && !config.DryRun {
+			report.write(log, archiveDir)
+		}
+	}()
+
+	// Post-processing runs exactly once on every path, regardless of upload success, and is part of the report
+	// This includes interrupted runs, qBittorrent doesn't call the program again for the torrent in hook mode
+	defer executePostProcessing(log, config, qbtArgs, report)
+
+	// Check if the torrent should be excluded from processing
+	if process, reason := shouldProcessTorrent(log, config, qbtArgs); !process {
+		log.Infof("ℹ️ Torrent excluded from processing: %s, skipping CrowdNFO upload", reason)
+		return nil
+	}
+
+	// Nothing can be uploaded if the content path can't be read, e.g. with a different Docker path mapping
+	if err := checkContentPath(qbtArgs); err != nil {
+		log.Warnf("⚠️ Skipping CrowdNFO upload - %v, but continuing with post-processing scripts...", err)
+		return nil
 	}
 
 	// Check UmlautAdaptarr for title changes
 	originalTitle, err := checkUmlautadaptarr(config, cleanJobName)
 	if err != nil {
-		log.Printf("❌ UmlautAdaptarr check failed: %v", err)
-		log.Printf("⚠️ Skipping CrowdNFO processing, but continuing with post-processing scripts...")
-
-		// Execute post-processing commands even if UmlautAdaptarr fails
-		executePostProcessing(config, qbtArgs)
-		return
+		log.Errorf("❌ UmlautAdaptarr check failed: %v", err)
+		log.Warnf("⚠️ Skipping CrowdNFO processing, but continuing with post-processing scripts...")
+		return nil
 	}
 
 	// Use original title if Umlautadaptarr made changes
 	if originalTitle != "" {
-		log.Printf("ℹ️ Using original title from UmlautAdaptarr: %s", originalTitle)
+		log.Infof("ℹ️ Using original title from UmlautAdaptarr: %s", originalTitle)
 		cleanJobName = originalTitle
+		report.ReleaseName = cleanJobName
+	}
+
+	// Validate the release name and strip website prefixes before anything is named after it
+	if config.ReleaseNames.Enabled {
+		nameCheck := checkReleaseName(log, config, cleanJobName, finalDir)
+		report.NameCheck = &nameCheck
+		if !nameCheck.Valid && config.ReleaseNames.SkipInvalid {
+			log.Warnf("⚠️ Skipping CrowdNFO upload - no valid release name found, but continuing with post-processing scripts...")
+			return nil
+		}
+		cleanJobName = nameCheck.Name
+		report.ReleaseName = cleanJobName
+	}
+
+	// Create archive directory (not in dry-run mode, nothing gets archived there)
+	archiveDir = filepath.Join(getCurrentDir(), "archive", cleanJobName)
+	if config.DryRun {
+		log.Infof("🧪 DRY RUN: Not creating archive directory %s", archiveDir)
+	} else if err := os.MkdirAll(archiveDir, 0755); err != nil {
+		log.Errorf("❌ Failed to create archive directory: %v", err)
+		archiveDir = ""
+		return nil
 	}
 
-	// Create archive directory
-	archiveDir := filepath.Join(getCurrentDir(), "archive", cleanJobName)
-	if err := os.MkdirAll(archiveDir, 0755); err != nil {
-		log.Fatal("Failed to create archive directory: ", err)
+	// Verify the release against included SFV/MD5/SHA files before anything is uploaded
+	verification := verifyChecksums(ctx, log, config, finalDir)
+	report.Verification = verification
+	if ctx.Err() != nil {
+		log.Warnf("⚠️ Processing interrupted during checksum verification")
+		return ctx.Err()
 	}
 
 	// Check if this is a season pack
 	if isSeasonPack(cleanJobName) || isSeasonPackFallback(finalDir) {
 		if isSeasonPack(cleanJobName) {
-			log.Printf("📦 Detected season pack via name pattern: %s", cleanJobName)
+			log.Infof("📦 Detected season pack via name pattern: %s", cleanJobName)
 		} else {
-			log.Printf("📦 Detected season pack via file count (≥3 episodes): %s", cleanJobName)
+			log.Infof("📦 Detected season pack via file count (≥3 episodes): %s", cleanJobName)
 		}
-		if err := processSeasonPack(config, finalDir, cleanJobName, qbtCategory, archiveDir, qbtArgs); err != nil {
-			log.Printf("❌ Season pack processing failed: %v", err)
-			return
+
+		strategy := seasonPackStrategy(config, qbtArgs.Category)
+		if strategy == seasonPackWhole || strategy == seasonPackBoth {
+			log.Infof("📦 Uploading season pack as one release (strategy: %s)", strategy)
+			if err := processSingleRelease(ctx, log, config, finalDir, cleanJobName, archiveDir, qbtArgs, report, verification); err != nil {
+				return err
+			}
 		}
-		log.Printf("✅ Season pack processing completed")
+		if strategy == seasonPackSplit || strategy == seasonPackBoth {
+			split, err := processSeasonPack(ctx, log, config, finalDir, cleanJobName, archiveDir, qbtArgs, report, verification)
+			if err != nil {
+				log.Errorf("❌ Season pack processing failed: %v", err)
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+			}
+
+			// Nothing split (split is false for failures before any episode was uploaded), handle it like a normal
+			// release unless the pack was already uploaded as a whole
+			if !split && strategy == seasonPackSplit {
+				log.Infof("ℹ️ Falling back to single release processing")
+				if err := processSingleRelease(ctx, log, config, finalDir, cleanJobName, archiveDir, qbtArgs, report, verification); err != nil {
+					return err
+				}
+			}
+		}
+		log.Infof("✅ Season pack processing completed")
+		return nil
+	}
+
+	if err := processSingleRelease(ctx, log, config, finalDir, cleanJobName, archiveDir, qbtArgs, report, verification); err != nil {
+		return err
+	}
+
+	log.Infof("✅ All processing completed successfully")
+
+	// Check and display update notification if available
+	displayUpdateNotification()
+	return nil
+}
+
+// displayUpdateNotification shows update information if available
+func displayUpdateNotification() {
+	updateMu.Lock()
+	defer updateMu.Unlock()
+
+	if !updateCheckDone {
 		return
 	}
 
+	if updateAvailable {
+		logInfof("🔔 Update available!")
+		if latestVersion != "" {
+			currentVersion := getCleanVersion()
+			logInfof("   Current version: %s", currentVersion)
+			logInfof("   Latest version:  %s", latestVersion)
+		}
+		logInfof("   Visit https://github.com/your-repo/releases for the latest version")
+	}
+}
+
+// processSingleRelease uploads NFO, MediaInfo, hash and file list of the whole content path as one release
+// Returns an error only if processing was interrupted
+func processSingleRelease(ctx context.Context, log *Logger, config *Config, finalDir, cleanJobName, archiveDir string, qbtArgs QBittorrentArgs, report *RunReport, verification *VerificationResult) error {
 	// Try to initialize MediaInfo (optional)
 	mediaInfoPath, hasMediaInfo := initializeMediaInfo(config.MediaInfoPath)
 	if !hasMediaInfo {
-		log.Println("ℹ️ MediaInfo not available - some features may be limited")
+		log.Infof("ℹ️ MediaInfo not available - some features may be limited")
 	}
 
 	// Try to find media file for MediaInfo generation
@@ -201,30 +359,48 @@ func main() {
 		}
 	}
 
+	// Don't feed MediaInfo with files whose extension lies about their content
+	playable := mediaFile == "" || isHashOnlyFile(mediaFile) || !config.ContentDetection.Enabled || isPlayableMediaFile(log, mediaFile)
+
 	// Generate MediaInfo and hash if media file found
-	if mediaFile != "" && hasMediaInfo {
-		log.Printf("⏳ Processing media file: %s", filepath.Base(mediaFile))
+	if mediaFile != "" && !playable {
+		log.Warnf("⚠️ Skipping MediaInfo generation - %s is not a media file", filepath.Base(mediaFile))
+	} else if mediaFile != "" && hasMediaInfo {
+		log.Infof("⏳ Processing media file: %s", filepath.Base(mediaFile))
 
 		// Generate MediaInfo JSON only for non-hash-only files
 		if !isHashOnlyFile(mediaFile) {
 			mediaInfoJSON, err = generateMediaInfoJSON(mediaFile, mediaInfoPath)
 			if err != nil {
-				log.Printf("⚠️ Failed to generate MediaInfo: %v", err)
+				log.Warnf("⚠️ Failed to generate MediaInfo: %v", err)
 			}
 		}
 	} else if mediaFile != "" && !hasMediaInfo {
-		log.Println("ℹ️ Skipping MediaInfo generation - MediaInfo not available")
+		log.Infof("ℹ️ Skipping MediaInfo generation - MediaInfo not available")
 	}
 
 	// Calculate hash for any file found (media or ISO/IMG)
+	var hashSkipped bool
+	var fileDigests FileDigests
 	if mediaFile != "" {
-		shouldHash, err := shouldCalculateHash(config, mediaFile)
+		shouldHash, err := shouldCalculateHash(log, config, mediaFile)
 		if err != nil {
-			log.Printf("⚠️ Failed to check file size for hash calculation: %v", err)
-		} else if shouldHash {
-			hash, err = calculateSHA256(mediaFile)
-			if err != nil {
-				log.Printf("⚠️ Failed to calculate SHA256: %v", err)
+			log.Warnf("⚠️ Failed to check file size for hash calculation: %v", err)
+		} else if !shouldHash {
+			hashSkipped = true
+		} else {
+			digests, err := cachedHashFile(ctx, log, config, mediaFile, config.Hashing.Algorithms)
+			if ctx.Err() != nil {
+				log.Warnf("⚠️ Processing interrupted: %v", err)
+				return ctx.Err()
+			}
+			if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+				log.Warnf("⚠️ Hash calculation timed out, uploading without hash")
+			} else if err != nil {
+				log.Warnf("⚠️ Failed to calculate SHA256: %v", err)
+			} else {
+				hash = digests["sha256"]
+				fileDigests = digests
 			}
 		}
 	}
@@ -232,7 +408,7 @@ func main() {
 	// Find NFO file (independent of media files)
 	nfoFile, err := findNFOFile(finalDir)
 	if err != nil {
-		log.Printf("ℹ️ No NFO file found")
+		log.Infof("ℹ️ No NFO file found")
 		nfoFile = "" // Set empty string for upload function
 	}
 
@@ -247,61 +423,41 @@ func main() {
 		}
 	}
 
+	// Don't publish MediaInfo and hashes of corrupt downloads
+	if verification != nil && !verification.Passed && config.Verification.BlockOnFailure {
+		log.Warnf("⚠️ Release failed checksum verification, not uploading MediaInfo and hash")
+		mediaInfoJSON = nil
+		hash = ""
+	}
+
+	releaseReport := report.newRelease(releaseName)
+	releaseReport.MediaFile = mediaFile
+	releaseReport.Hash = hash
+	releaseReport.Digests = fileDigests
+	if hashSkipped {
+		releaseReport.HashSkipped = true
+		releaseReport.HashSkipReason = fmt.Sprintf("max_hash_file_size (%s)", config.MaxHashFileSize)
+	}
+
 	// Upload to CrowdNFO API (works with or without media files/NFO)
-	if err := uploadToCrowdNFO(config, releaseName, qbtCategory, hash, finalDir, mediaInfoJSON, nfoFile, archiveDir); err != nil {
+	if err := uploadToCrowdNFO(log, config, releaseName, qbtArgs, hash, finalDir, mediaInfoJSON, nfoFile, archiveDir, releaseReport); err != nil {
 		errStr := err.Error()
 		if strings.HasPrefix(errStr, "partial_failure:") {
-			log.Printf("⚠️ Upload completed with partial success: %s", strings.TrimPrefix(errStr, "partial_failure:"))
+			log.Warnf("⚠️ Upload completed with partial success: %s", strings.TrimPrefix(errStr, "partial_failure:"))
 		} else if strings.HasPrefix(errStr, "total_failure:") {
-			log.Printf("❌ Upload process failed: %s", strings.TrimPrefix(errStr, "total_failure:"))
+			log.Errorf("❌ Upload process failed: %s", strings.TrimPrefix(errStr, "total_failure:"))
 		} else {
-			log.Printf("❌ Upload process failed: %v", err)
+			log.Errorf("❌ Upload process failed: %v", err)
 		}
 	}
 
-	log.Printf("✅ All processing completed successfully")
-
-	// Check and display update notification if available
-	displayUpdateNotification()
-
-	// Execute post-processing commands (always run, regardless of upload success)
-	executePostProcessing(config, qbtArgs)
-}
-
-// displayUpdateNotification shows update information if available
-func displayUpdateNotification() {
-	if !updateCheckDone {
-		return
-	}
-
-	if updateAvailable {
-		log.Printf("🔔 Update available!")
-		if latestVersion != "" {
-			currentVersion := getCleanVersion()
-			log.Printf("   Current version: %s", currentVersion)
-			log.Printf("   Latest version:  %s", latestVersion)
-		}
-		log.Printf("   Visit https://github.com/your-repo/releases for the latest version")
-	}
+	return nil
 }
 
 // isSeasonPack determines if the given job name corresponds to a season pack
 func isSeasonPack(jobName string) bool {
-	// First check for traditional season patterns (S\d{2,4}) but NOT episodes (SxxExx)
-	seasonPattern := regexp.MustCompile(`(?i)\bS\d{2,4}\b`)
-	episodePattern := regexp.MustCompile(`(?i)\bS\d{2,4}E\d{2,4}\b`)
-
-	if seasonPattern.MatchString(jobName) && !episodePattern.MatchString(jobName) {
-		return true
-	}
-
-	// Check for ISO date format years (S2024, S2023, etc.)
-	isoYearPattern := regexp.MustCompile(`(?i)\bS(20\d{2})\b`)
-	if isoYearPattern.MatchString(jobName) {
-		return true
-	}
-
-	return false
+	// A season (S01, or S2024 for shows numbered by year) but no episode or air date
+	return parseReleaseName(jobName).IsSeasonPack()
 }
 
 // isSeasonPackFallback checks if a directory should be treated as season pack based on video file count
@@ -316,124 +472,251 @@ func isSeasonPackFallback(finalDir string) bool {
 }
 
 // processSeasonPack handles the processing of season packs
-func processSeasonPack(config *Config, finalDir, cleanJobName, qbtCategory, archiveDir string, qbtArgs QBittorrentArgs) error {
+// Returns false if the pack can't be split (fewer than 3 videos or no valid episodes), nothing was uploaded then
+func processSeasonPack(ctx context.Context, log *Logger, config *Config, finalDir, cleanJobName, archiveDir string, qbtArgs QBittorrentArgs, report *RunReport, verification *VerificationResult) (bool, error) {
 	// Check if this is actually a season pack by counting video files
 	if !isSeasonPackFallback(finalDir) {
-		log.Printf("ℹ️ Less than 3 video files found, processing as single release")
-		return nil
+		log.Infof("ℹ️ Less than 3 video files found, not splitting into episodes")
+		return false, nil
 	}
 
 	// Try to initialize MediaInfo (optional)
 	mediaInfoPath, hasMediaInfo := initializeMediaInfo(config.MediaInfoPath)
 	if !hasMediaInfo {
-		log.Println("ℹ️ MediaInfo not available - some features may be limited")
+		log.Infof("ℹ️ MediaInfo not available - some features may be limited")
 	}
 
 	// Find all video files in the season pack
 	videoFiles, err := findAllVideoFiles(finalDir)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	if len(videoFiles) == 0 {
-		log.Println("No video files found in season pack")
-		return nil
+		log.Infof("No video files found in season pack")
+		return false, nil
 	}
 
-	log.Printf("🔍 Found %d video files in season pack", len(videoFiles))
+	log.Infof("🔍 Found %d video files in season pack", len(videoFiles))
 
 	// Extract episode information for each video file
 	episodes := make([]EpisodeInfo, 0)
 	generalNFO := findGeneralNFO(finalDir)
 
+	if packInfo := parseReleaseName(cleanJobName); packInfo.IsMultiSeason() {
+		log.Infof("📚 Multi-season pack, determining the season per file or folder")
+	}
+
 	for _, videoFile := range videoFiles {
-		episodeInfo := extractEpisodeInfo(videoFile, cleanJobName, generalNFO)
-		if episodeInfo.ReleaseName != "" { // Only process valid episodes
-			episodes = append(episodes, episodeInfo)
+		seasonPackName, inSeasonDir, seasonNFO := episodeSeasonPack(videoFile, finalDir, cleanJobName, generalNFO)
+		episodeInfo := extractEpisodeInfo(log, videoFile, seasonPackName, inSeasonDir, seasonNFO)
+		if episodeInfo.ReleaseName == "" { // Only process valid episodes
+			continue
+		}
+		if episodeInfo.Special && config.SeasonPack.Specials == specialsPack {
+			log.Infof("ℹ️ Special %s stays part of the season pack, not uploaded as own release", videoFile.Name)
+			continue
 		}
+		episodes = append(episodes, episodeInfo)
 	}
 
 	if len(episodes) == 0 {
-		log.Println("No valid episodes found in season pack")
-		return nil
+		log.Infof("ℹ️ No valid episodes found in season pack")
+		return false, nil
 	}
 
-	log.Printf("📺 Processing %d episodes", len(episodes))
+	hashWorkers, uploadWorkers := getSeasonPackWorkers(config)
+	log.Infof("📺 Processing %d episodes (%d hash workers, %d upload workers)", len(episodes), hashWorkers, uploadWorkers)
+	report.SeasonPack = true
 
-	// Process each episode
-	successCount := 0
+	// Episodes run through two bounded stages: hashing/MediaInfo reads the whole file and is limited separately
+	// from the uploads, so a spinning disk is not thrashed by parallel reads. Each episode logs into its own
+	// buffer, which is flushed in episode order once the episode is done.
+	results := make([]*episodeResult, len(episodes))
 	for i, episode := range episodes {
-		log.Printf("📄 Processing episode %d/%d: %s", i+1, len(episodes), episode.ReleaseName)
+		results[i] = &episodeResult{
+			log:    log.newBuffer(),
+			report: report.newRelease(episode.ReleaseName),
+			done:   make(chan struct{}),
+		}
+	}
 
-		// Calculate SHA256 for this episode (check file size limit first)
-		var hash string
-		shouldHash, err := shouldCalculateHash(config, episode.VideoFile.Path)
-		if err != nil {
-			log.Printf("⚠️ Failed to check file size for hash calculation: %v", err)
-		} else if shouldHash {
-			hash, err = calculateSHA256(episode.VideoFile.Path)
-			if err != nil {
-				log.Printf("❌ Failed to calculate SHA256 for %s: %v", episode.ReleaseName, err)
-				continue
+	hashJobs := make(chan int)
+	uploadJobs := make(chan int)
+
+	var hashWG sync.WaitGroup
+	for w := 0; w < hashWorkers; w++ {
+		hashWG.Add(1)
+		go func() {
+			defer hashWG.Done()
+			for i := range hashJobs {
+				// Episodes not started before an interrupt are skipped silently
+				if ctx.Err() == nil && prepareEpisode(ctx, config, episodes[i], i, len(episodes), mediaInfoPath, hasMediaInfo, verification, results[i]) {
+					uploadJobs <- i
+				} else {
+					close(results[i].done)
+				}
+			}
+		}()
+	}
+
+	var uploadWG sync.WaitGroup
+	for w := 0; w < uploadWorkers; w++ {
+		uploadWG.Add(1)
+		go func() {
+			defer uploadWG.Done()
+			for i := range uploadJobs {
+				result := results[i]
+				// Don't log additional error message - the upload function already logged the details
+				err := uploadEpisodeToCrowdNFO(result.log, config, episodes[i], qbtArgs, result.hash, result.mediaInfoJSON, archiveDir, result.report)
+				result.success = err == nil
+				close(result.done)
 			}
+		}()
+	}
+
+	go func() {
+		for i := range episodes {
+			hashJobs <- i
 		}
+		close(hashJobs)
+		hashWG.Wait()
+		close(uploadJobs)
+	}()
 
-		// Generate MediaInfo JSON for this episode
-		var mediaInfoJSON []byte
-		if hasMediaInfo {
-			mediaInfoJSON, err = generateMediaInfoJSON(episode.VideoFile.Path, mediaInfoPath)
-			if err != nil {
-				log.Printf("⚠️ Failed to generate MediaInfo for %s: %v", episode.ReleaseName, err)
-			}
+	successCount := 0
+	for _, result := range results {
+		<-result.done
+		log.flush(result.log)
+		if result.success {
+			successCount++
 		}
+	}
+	uploadWG.Wait()
 
-		// Upload this episode to CrowdNFO API with file list
-		err = uploadEpisodeToCrowdNFO(config, episode, qbtCategory, hash, mediaInfoJSON, archiveDir)
-		if err != nil {
-			// Don't log additional error message - the upload function already logged the details
-			continue
+	if ctx.Err() != nil {
+		return true, fmt.Errorf("interrupted after %d/%d episodes: %v", successCount, len(episodes), ctx.Err())
+	}
+
+	log.Infof("✅ Season pack completed: %d/%d episodes successful", successCount, len(episodes))
+	return true, nil
+}
+
+// episodeResult carries the state of one season pack episode between the hash and upload stage
+type episodeResult struct {
+	log           *Logger
+	report        *ReleaseReport
+	hash          string
+	mediaInfoJSON []byte
+	success       bool
+	done          chan struct{}
+}
+
+// getSeasonPackWorkers returns the configured number of hash and upload workers for season packs
+func getSeasonPackWorkers(config *Config) (int, int) {
+	hashWorkers := config.SeasonPack.HashWorkers
+	if hashWorkers < 1 {
+		hashWorkers = defaultHashWorkers
+	}
+	uploadWorkers := config.SeasonPack.UploadWorkers
+	if uploadWorkers < 1 {
+		uploadWorkers = defaultUploadWorkers
+	}
+	return hashWorkers, uploadWorkers
+}
+
+// seasonPackStrategy returns how a season pack is uploaded, category_strategies override the default strategy
+func seasonPackStrategy(config *Config, category string) string {
+	for configured, strategy := range config.SeasonPack.CategoryStrategies {
+		if strings.EqualFold(strings.TrimSpace(configured), strings.TrimSpace(category)) {
+			return strategy
 		}
+	}
+	if config.SeasonPack.Strategy == "" {
+		return seasonPackSplit
+	}
+	return config.SeasonPack.Strategy
+}
+
+// prepareEpisode calculates hash and MediaInfo of an episode, returns false if the episode can't be uploaded
+func prepareEpisode(ctx context.Context, config *Config, episode EpisodeInfo, index, total int, mediaInfoPath string, hasMediaInfo bool, verification *VerificationResult, result *episodeResult) bool {
+	log := result.log
+	log.Infof("📄 Processing episode %d/%d: %s", index+1, total, episode.ReleaseName)
+	result.report.MediaFile = episode.VideoFile.Path
 
-		successCount++
+	// Don't publish MediaInfo and hashes of corrupt downloads
+	if config.Verification.BlockOnFailure && verification.fileFailed(episode.VideoFile.Path) {
+		log.Warnf("⚠️ %s failed checksum verification, not uploading MediaInfo and hash", episode.ReleaseName)
+		return true
 	}
 
-	log.Printf("✅ Season pack completed: %d/%d episodes successful", successCount, len(episodes))
+	// Calculate SHA256 for this episode (check file size limit first)
+	shouldHash, err := shouldCalculateHash(log, config, episode.VideoFile.Path)
+	if err != nil {
+		log.Warnf("⚠️ Failed to check file size for hash calculation: %v", err)
+	} else if !shouldHash {
+		result.report.HashSkipped = true
+		result.report.HashSkipReason = fmt.Sprintf("max_hash_file_size (%s)", config.MaxHashFileSize)
+	} else {
+		digests, err := cachedHashFile(ctx, log, config, episode.VideoFile.Path, config.Hashing.Algorithms)
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			log.Warnf("⚠️ Hash calculation for %s timed out, continuing without hash", episode.ReleaseName)
+		} else if err != nil {
+			log.Errorf("❌ Failed to calculate SHA256 for %s: %v", episode.ReleaseName, err)
+			return false
+		} else {
+			result.hash = digests["sha256"]
+			result.report.Hash = result.hash
+			result.report.Digests = digests
+		}
+	}
 
-	// Execute post-processing commands for season packs
-	executePostProcessing(config, qbtArgs)
+	// Generate MediaInfo JSON for this episode
+	if hasMediaInfo && config.ContentDetection.Enabled && !isPlayableMediaFile(log, episode.VideoFile.Path) {
+		log.Warnf("⚠️ Skipping MediaInfo for %s - file is not a media file", episode.ReleaseName)
+	} else if hasMediaInfo {
+		result.mediaInfoJSON, err = generateMediaInfoJSON(episode.VideoFile.Path, mediaInfoPath)
+		if err != nil {
+			log.Warnf("⚠️ Failed to generate MediaInfo for %s: %v", episode.ReleaseName, err)
+		}
+	}
 
-	return nil
+	return true
 }
 
 // executePostProcessing runs post-processing commands based on configuration
-func executePostProcessing(config *Config, qbtArgs QBittorrentArgs) {
+func executePostProcessing(log *Logger, config *Config, qbtArgs QBittorrentArgs, report *RunReport) {
 	if config.PostProcessing.Global.Enabled {
-		runPostProcessCommand("global", config.PostProcessing.Global, qbtArgs)
+		runPostProcessCommand(log, config, "global", config.PostProcessing.Global, qbtArgs, report)
 	}
 
 	// Check for category-specific post-processing
 	if config.PostProcessing.Categories != nil {
 		// First try the exact qBittorrent category
 		if cmd, exists := config.PostProcessing.Categories[qbtArgs.Category]; exists && cmd.Enabled {
-			runPostProcessCommand(fmt.Sprintf("category '%s'", qbtArgs.Category), cmd, qbtArgs)
+			runPostProcessCommand(log, config, fmt.Sprintf("category '%s'", qbtArgs.Category), cmd, qbtArgs, report)
 			return
 		}
 
 		// Try lowercase version
 		if cmd, exists := config.PostProcessing.Categories[strings.ToLower(qbtArgs.Category)]; exists && cmd.Enabled {
-			runPostProcessCommand(fmt.Sprintf("category '%s'", strings.ToLower(qbtArgs.Category)), cmd, qbtArgs)
+			runPostProcessCommand(log, config, fmt.Sprintf("category '%s'", strings.ToLower(qbtArgs.Category)), cmd, qbtArgs, report)
 			return
 		}
 	}
 }
 
 // runPostProcessCommand executes a post-processing command with qBittorrent arguments and placeholders
-func runPostProcessCommand(configType string, cmd PostProcessCommand, qbtArgs QBittorrentArgs) {
+func runPostProcessCommand(log *Logger, config *Config, configType string, cmd PostProcessCommand, qbtArgs QBittorrentArgs, report *RunReport) {
 	if cmd.Command == "" {
 		return
 	}
 
-	log.Printf("🔧 Running %s post-processing: %s", configType, cmd.Command)
+	if config.DryRun {
+		log.Infof("🧪 DRY RUN: Would run %s post-processing: %s", configType, cmd.Command)
+	} else {
+		log.Infof("🔧 Running %s post-processing: %s", configType, cmd.Command)
+	}
 
 	// Build command arguments
 	args := make([]string, 0)
@@ -442,22 +725,18 @@ func runPostProcessCommand(configType string, cmd PostProcessCommand, qbtArgs QB
 	if len(cmd.Arguments) > 0 {
 		for _, arg := range cmd.Arguments {
 			// Replace qBittorrent placeholders
-			arg = strings.ReplaceAll(arg, "%N", qbtArgs.TorrentName)
-			arg = strings.ReplaceAll(arg, "%F", qbtArgs.ContentPath)
-			arg = strings.ReplaceAll(arg, "%L", qbtArgs.Category)
-			arg = strings.ReplaceAll(arg, "%I", qbtArgs.InfoHash)
-			arg = strings.ReplaceAll(arg, "%D", qbtArgs.SavePath)
-			arg = strings.ReplaceAll(arg, "%G", qbtArgs.Tags)
-			arg = strings.ReplaceAll(arg, "%J", qbtArgs.InfoHashV2)
-			arg = strings.ReplaceAll(arg, "%K", qbtArgs.TorrentID)
-			arg = strings.ReplaceAll(arg, "%R", qbtArgs.RootPath)
-			arg = strings.ReplaceAll(arg, "%T", qbtArgs.Tracker)
-			arg = strings.ReplaceAll(arg, "%Z", qbtArgs.TorrentSize)
-			arg = strings.ReplaceAll(arg, "%C", qbtArgs.NumberFiles)
+			for _, field := range qbtArgFields {
+				arg = strings.ReplaceAll(arg, field.Placeholder, *field.Value(&qbtArgs))
+			}
 			args = append(args, arg)
 		}
 	}
 
+	if config.DryRun {
+		log.Infof("   Arguments: %q", args)
+		return
+	}
+
 	// Execute the command
 	execCmd := exec.Command(cmd.Command, args...)
 
@@ -482,15 +761,25 @@ func runPostProcessCommand(configType string, cmd PostProcessCommand, qbtArgs QB
 
 	// Capture output
 	output, err := execCmd.CombinedOutput()
+	result := PostProcessReport{Name: configType, Command: cmd.Command, Arguments: args}
+	if err != nil {
+		result.Error = err.Error()
+		result.ExitCode = -1
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			result.ExitCode = exitErr.ExitCode()
+		}
+	}
+	report.addPostProcessing(result)
+
 	if err != nil {
-		log.Printf("❌ Post-processing command failed: %v", err)
+		log.Errorf("❌ Post-processing command failed: %v", err)
 		if len(output) > 0 {
-			log.Printf("   Output: %s", string(output))
+			log.Infof("   Output: %s", string(output))
 		}
 	} else {
-		log.Printf("✅ Post-processing command completed successfully")
+		log.Infof("✅ Post-processing command completed successfully")
 		if len(output) > 0 {
-			log.Printf("   Output: %s", string(output))
+			log.Infof("   Output: %s", string(output))
 		}
 	}
 }
@@ -4,7 +4,6 @@ import (
 	"archive/zip"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"os"
 	"os/exec"
@@ -29,10 +28,10 @@ func findMediaInfoBinary(configPath string) (string, error) {
 	// 1. Check if path is specified in config
 	if configPath != "" {
 		if isExecutable(configPath) {
-			log.Printf("ℹ️ Using MediaInfo from config: %s", configPath)
+			logDebugf("ℹ️ Using MediaInfo from config: %s", configPath)
 			return configPath, nil
 		}
-		log.Printf("Warning: MediaInfo path from config is not executable: %s", configPath)
+		logWarnf("⚠️ MediaInfo path from config is not executable: %s", configPath)
 	}
 
 	// 2. Check in the same directory as the executable
@@ -42,14 +41,14 @@ func findMediaInfoBinary(configPath string) (string, error) {
 		localPath += ".exe"
 	}
 	if isExecutable(localPath) {
-		log.Printf("ℹ️ Using local MediaInfo: %s", localPath)
+		logDebugf("ℹ️ Using local MediaInfo: %s", localPath)
 		return localPath, nil
 	}
 
 	// 3. Check in PATH
 	pathBinary, err := exec.LookPath("mediainfo")
 	if err == nil && isExecutable(pathBinary) {
-		log.Printf("ℹ️ Using MediaInfo from PATH: %s", pathBinary)
+		logDebugf("ℹ️ Using MediaInfo from PATH: %s", pathBinary)
 		return pathBinary, nil
 	}
 
@@ -57,7 +56,7 @@ func findMediaInfoBinary(configPath string) (string, error) {
 	standardPaths := getStandardMediaInfoPaths()
 	for _, path := range standardPaths {
 		if isExecutable(path) {
-			log.Printf("ℹ️ Using MediaInfo from default path: %s", path)
+			logDebugf("ℹ️ Using MediaInfo from default path: %s", path)
 			return path, nil
 		}
 	}
@@ -65,10 +64,10 @@ func findMediaInfoBinary(configPath string) (string, error) {
 	// 5. For Windows, try to download MediaInfo CLI automatically
 	if runtime.GOOS == "windows" {
 		if downloadedPath, err := downloadMediaInfoForWindows(executableDir); err == nil {
-			log.Printf("Successfully downloaded MediaInfo CLI: %s", downloadedPath)
+			logInfof("Successfully downloaded MediaInfo CLI: %s", downloadedPath)
 			return downloadedPath, nil
 		} else {
-			log.Printf("Warning: Failed to download MediaInfo CLI: %v", err)
+			logWarnf("⚠️ Failed to download MediaInfo CLI: %v", err)
 		}
 	}
 
@@ -79,7 +78,7 @@ func findMediaInfoBinary(configPath string) (string, error) {
 func downloadMediaInfoForWindows(targetDir string) (string, error) {
 	const mediaInfoURL = "https://mediaarea.net/download/binary/mediainfo/25.04/MediaInfo_CLI_25.04_Windows_x64.zip"
 
-	log.Println("MediaInfo not found. Attempting to download MediaInfo CLI for Windows...")
+	logInfof("MediaInfo not found. Attempting to download MediaInfo CLI for Windows...")
 
 	// Download the zip file
 	zipPath := filepath.Join(targetDir, "mediainfo_cli.zip")
@@ -207,17 +206,17 @@ func isExecutable(path string) bool {
 func initializeMediaInfo(configPath string) (string, bool) {
 	mediaInfoPath, err := findMediaInfoBinary(configPath)
 	if err != nil {
-		log.Printf("Warning: %v", err)
+		logWarnf("⚠️ %v", err)
 		return "", false
 	}
 
 	// Test if the binary works
 	if err := exec.Command(mediaInfoPath, "--Version").Run(); err != nil {
-		log.Printf("Warning: MediaInfo binary is not functional: %v", err)
+		logWarnf("⚠️ MediaInfo binary is not functional: %v", err)
 		return "", false
 	}
 
-	// log.Println("MediaInfo binary found and verified successfully")
+	// logInfof("MediaInfo binary found and verified successfully")
 	return mediaInfoPath, true
 }
 
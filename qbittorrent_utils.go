@@ -0,0 +1,357 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const defaultPollInterval = 60
+
+// QBittorrentTorrent represents a torrent as returned by /api/v2/torrents/info
+type QBittorrentTorrent struct {
+	Hash         string  `json:"hash"`
+	InfoHashV1   string  `json:"infohash_v1"`
+	InfoHashV2   string  `json:"infohash_v2"`
+	Name         string  `json:"name"`
+	ContentPath  string  `json:"content_path"`
+	SavePath     string  `json:"save_path"`
+	RootPath     string  `json:"root_path"`
+	Category     string  `json:"category"`
+	Tags         string  `json:"tags"`
+	Tracker      string  `json:"tracker"`
+	TotalSize    int64   `json:"total_size"`
+	Progress     float64 `json:"progress"`
+	CompletionOn int64   `json:"completion_on"`
+}
+
+// ServeState tracks which torrents have already been processed in serve mode
+type ServeState struct {
+	Processed map[string]time.Time `json:"processed"`
+}
+
+// qbittorrentClient is a minimal client for the qBittorrent WebUI API
+type qbittorrentClient struct {
+	config  *Config
+	baseURL string
+	client  *http.Client
+}
+
+// newQBittorrentClient creates a WebUI API client with a cookie jar for the session
+func newQBittorrentClient(config *Config) (*qbittorrentClient, error) {
+	if config.QBittorrent.BaseURL == "" {
+		return nil, fmt.Errorf("qbittorrent.base_url is not configured")
+	}
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := createHTTPClient(config, 30*time.Second)
+	client.Jar = jar
+
+	return &qbittorrentClient{
+		config:  config,
+		baseURL: strings.TrimSuffix(config.QBittorrent.BaseURL, "/"),
+		client:  client,
+	}, nil
+}
+
+// login authenticates against the WebUI and stores the session cookie
+func (c *qbittorrentClient) login() error {
+	form := url.Values{}
+	form.Set("username", c.config.QBittorrent.Username)
+	form.Set("password", c.config.QBittorrent.Password)
+
+	req, err := http.NewRequest("POST", c.baseURL+"/api/v2/auth/login", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Referer", c.baseURL)
+	req.Header.Set("User-Agent", getUserAgent())
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to connect to qBittorrent WebUI: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusForbidden {
+		return fmt.Errorf("qBittorrent WebUI login forbidden (too many failed attempts?)")
+	}
+	if resp.StatusCode != http.StatusOK || strings.TrimSpace(string(body)) != "Ok." {
+		return fmt.Errorf("qBittorrent WebUI login failed (status %d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
+
+// get performs an authenticated GET request, logging in again once if the session expired
+func (c *qbittorrentClient) get(path string, query url.Values, target interface{}) error {
+	apiURL := c.baseURL + path
+	if len(query) > 0 {
+		apiURL += "?" + query.Encode()
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		req, err := http.NewRequest("GET", apiURL, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Referer", c.baseURL)
+		req.Header.Set("User-Agent", getUserAgent())
+
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to connect to qBittorrent WebUI: %v", err)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read qBittorrent response: %v", err)
+		}
+
+		if resp.StatusCode == http.StatusForbidden && attempt == 0 {
+			if err := c.login(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("qBittorrent API error (status %d): %s", resp.StatusCode, string(body))
+		}
+
+		if err := json.Unmarshal(body, target); err != nil {
+			return fmt.Errorf("failed to parse qBittorrent response: %v", err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("qBittorrent API request to %s was not authorized", path)
+}
+
+// getCompletedTorrents returns all torrents qBittorrent reports as completed
+func (c *qbittorrentClient) getCompletedTorrents() ([]QBittorrentTorrent, error) {
+	var torrents []QBittorrentTorrent
+	query := url.Values{}
+	query.Set("filter", "completed")
+
+	if err := c.get("/api/v2/torrents/info", query, &torrents); err != nil {
+		return nil, err
+	}
+	return torrents, nil
+}
+
+// getFileCount returns the number of files in a torrent
+func (c *qbittorrentClient) getFileCount(hash string) (int, error) {
+	var files []json.RawMessage
+	query := url.Values{}
+	query.Set("hash", hash)
+
+	if err := c.get("/api/v2/torrents/files", query, &files); err != nil {
+		return 0, err
+	}
+	return len(files), nil
+}
+
+// buildQBittorrentArgs converts an API torrent into the same arguments qBittorrent passes to external programs
+func (c *qbittorrentClient) buildQBittorrentArgs(torrent QBittorrentTorrent) QBittorrentArgs {
+	numberFiles := ""
+	if count, err := c.getFileCount(torrent.Hash); err != nil {
+		logWarnf("⚠️ Failed to get file count for %s: %v", torrent.Name, err)
+	} else {
+		numberFiles = strconv.Itoa(count)
+	}
+
+	// The API separates tags with ", " while %G uses ","
+	tags := make([]string, 0)
+	for _, tag := range strings.Split(torrent.Tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	infoHash := torrent.InfoHashV1
+	if infoHash == "" && torrent.InfoHashV2 == "" {
+		infoHash = torrent.Hash
+	}
+
+	return QBittorrentArgs{
+		TorrentName: torrent.Name,
+		ContentPath: torrent.ContentPath,
+		Category:    torrent.Category,
+		InfoHash:    infoHash,
+		SavePath:    torrent.SavePath,
+		Tags:        strings.Join(tags, ","),
+		InfoHashV2:  torrent.InfoHashV2,
+		TorrentID:   torrent.Hash,
+		RootPath:    torrent.RootPath,
+		Tracker:     torrent.Tracker,
+		TorrentSize: strconv.FormatInt(torrent.TotalSize, 10),
+		NumberFiles: numberFiles,
+	}
+}
+
+// getServeStatePath returns the location of the serve mode state file next to the config file
+func getServeStatePath() string {
+	return filepath.Join(getCurrentDir(), "crowdclient-serve-state.json")
+}
+
+// loadServeState loads the set of already processed torrents
+func loadServeState() (*ServeState, error) {
+	state := &ServeState{}
+	if err := readJSONFile(getServeStatePath(), state); err != nil {
+		return nil, err
+	}
+	if state.Processed == nil {
+		state.Processed = make(map[string]time.Time)
+	}
+	return state, nil
+}
+
+// markProcessed records a torrent as processed and persists the state immediately
+func (s *ServeState) markProcessed(hash string) {
+	s.Processed[hash] = time.Now()
+	if err := writeJSONFile(getServeStatePath(), s); err != nil {
+		logWarnf("⚠️ Failed to save serve state: %v", err)
+	}
+}
+
+// runServe polls the qBittorrent WebUI API for completed torrents and processes new ones
+func runServe(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	once := flags.Bool("once", false, "process completed torrents once and exit")
+	skipExisting := flags.Bool("skip-existing", false, "mark all currently completed torrents as processed without uploading")
+	flags.Parse(args)
+
+	config, err := loadConfig()
+	if err != nil {
+		logFatalf("❌ Failed to load configuration: %v", err)
+	}
+
+	client, err := newQBittorrentClient(config)
+	if err != nil {
+		logFatalf("❌ Failed to create qBittorrent client: %v", err)
+	}
+
+	if err := client.login(); err != nil {
+		logFatalf("❌ %v", err)
+	}
+	logInfof("✅ Logged in to qBittorrent WebUI at %s", client.baseURL)
+
+	state, err := loadServeState()
+	if err != nil {
+		logFatalf("❌ Failed to load serve state: %v", err)
+	}
+
+	pollInterval := time.Duration(config.QBittorrent.PollInterval) * time.Second
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval * time.Second
+	}
+
+	// Stop between torrents on SIGINT/SIGTERM instead of aborting an upload halfway
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+
+	// waitForNextPoll sleeps until the next poll, returns false on SIGINT/SIGTERM
+	waitForNextPoll := func() bool {
+		select {
+		case <-stop:
+			logInfof("ℹ️ Shutting down serve mode")
+			return false
+		case <-time.After(pollInterval):
+			return true
+		}
+	}
+
+	for {
+		torrents, err := client.getCompletedTorrents()
+		if err != nil {
+			// Without the list -skip-existing would mark nothing, so the state is only written after a successful fetch
+			logErrorf("❌ Failed to fetch completed torrents: %v", err)
+			if *once || !waitForNextPoll() {
+				return
+			}
+			continue
+		}
+
+		for _, torrent := range torrents {
+			if _, done := state.Processed[torrent.Hash]; done {
+				continue
+			}
+
+			if *skipExisting {
+				state.Processed[torrent.Hash] = time.Now()
+				continue
+			}
+
+			select {
+			case <-stop:
+				logInfof("ℹ️ Shutting down serve mode")
+				return
+			default:
+			}
+
+			// A content path this process can't see (e.g. different Docker path mapping) must not be marked as processed
+			qbtArgs := client.buildQBittorrentArgs(torrent)
+			err := validateQBittorrentArgs(config, qbtArgs)
+			if err == nil {
+				err = checkContentPath(qbtArgs)
+			}
+			if err != nil {
+				logWarnf("⚠️ Skipping %s, it will be checked again: %v", torrent.Name, err)
+				continue
+			}
+
+			logInfof("📥 New completed torrent: %s", torrent.Name)
+			if err := processTorrent(context.Background(), config, qbtArgs); err != nil {
+				logWarnf("⚠️ %s not completed, it will be processed again: %v", torrent.Name, err)
+				continue
+			}
+			if config.DryRun {
+				// Only remember the torrent for this run, the state file stays untouched
+				state.Processed[torrent.Hash] = time.Now()
+				continue
+			}
+			state.markProcessed(torrent.Hash)
+		}
+
+		if *skipExisting && !config.DryRun {
+			if err := writeJSONFile(getServeStatePath(), state); err != nil {
+				logWarnf("⚠️ Failed to save serve state: %v", err)
+			}
+			logInfof("ℹ️ Marked %d completed torrents as processed", len(state.Processed))
+			*skipExisting = false
+		}
+
+		// Replay failed uploads while we are running anyway
+		if config.UploadQueue.Enabled {
+			if queued, err := loadUploadQueue(); err == nil && len(queued) > 0 {
+				if err := retryUploadQueue(config); err != nil {
+					logWarnf("⚠️ Failed to process upload queue: %v", err)
+				}
+			}
+		}
+
+		if *once || !waitForNextPoll() {
+			return
+		}
+	}
+}
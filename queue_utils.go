@@ -0,0 +1,266 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"time"
+)
+
+const (
+	defaultQueueMaxAttempts = 10
+	queueBaseRetryDelay     = 5 * time.Minute
+	queueMaxRetryDelay      = 12 * time.Hour
+)
+
+// QueuedUpload is a failed upload persisted for a later retry
+type QueuedUpload struct {
+	ID               string           `json:"id"`
+	Uploader         string           `json:"uploader,omitempty"` // Uploader name, empty for CrowdNFO
+	Kind             string           `json:"kind"`               // "file" or "filelist"
+	ReleaseName      string           `json:"release_name"`
+	Category         string           `json:"category"`
+	Hash             string           `json:"hash,omitempty"`
+	FileType         string           `json:"file_type,omitempty"`
+	OriginalFileName string           `json:"original_file_name,omitempty"`
+	Data             []byte           `json:"data,omitempty"`
+	FileList         *FileListRequest `json:"file_list,omitempty"`
+	ArchiveDir       string           `json:"archive_dir,omitempty"`
+	Attempts         int              `json:"attempts"`
+	LastError        string           `json:"last_error"`
+	QueuedAt         time.Time        `json:"queued_at"`
+	NextAttempt      time.Time        `json:"next_attempt"`
+}
+
+// getQueuePath returns the location of the upload queue next to the config file
+func getQueuePath() string {
+	return filepath.Join(getCurrentDir(), "crowdclient-queue.json")
+}
+
+// getQueueMaxAttempts returns the configured number of attempts before a queued upload is dropped
+func getQueueMaxAttempts(config *Config) int {
+	if config.UploadQueue.MaxAttempts > 0 {
+		return config.UploadQueue.MaxAttempts
+	}
+	return defaultQueueMaxAttempts
+}
+
+// getQueueRetryDelay returns the exponential backoff delay after the given number of attempts
+func getQueueRetryDelay(attempts int) time.Duration {
+	delay := queueBaseRetryDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= queueMaxRetryDelay {
+			return queueMaxRetryDelay
+		}
+	}
+	return delay
+}
+
+// newQueueID generates a random identifier for a queue entry
+func newQueueID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// updateUploadQueue loads the queue, applies fn and saves the result while holding the queue lock
+func updateUploadQueue(fn func(items []QueuedUpload) []QueuedUpload) error {
+	queuePath := getQueuePath()
+
+	return withFileLock(queuePath, func() error {
+		var items []QueuedUpload
+		if err := readJSONFile(queuePath, &items); err != nil {
+			return fmt.Errorf("failed to read upload queue: %v", err)
+		}
+
+		return writeJSONFile(queuePath, fn(items))
+	})
+}
+
+// loadUploadQueue returns a snapshot of all queued uploads
+func loadUploadQueue() ([]QueuedUpload, error) {
+	var items []QueuedUpload
+	if err := withFileLock(getQueuePath(), func() error {
+		return readJSONFile(getQueuePath(), &items)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to read upload queue: %v", err)
+	}
+	return items, nil
+}
+
+// enqueueUpload persists a failed upload if the error is retryable and the queue is enabled, reporting whether it was queued
+func enqueueUpload(log *Logger, config *Config, item QueuedUpload, uploadErr error) bool {
+	if !config.UploadQueue.Enabled || !isRetryableUploadError(uploadErr) {
+		return false
+	}
+
+	now := time.Now()
+	item.ID = newQueueID()
+	item.Attempts = 1
+	item.LastError = uploadErr.Error()
+	item.QueuedAt = now
+	item.NextAttempt = now.Add(getQueueRetryDelay(item.Attempts))
+
+	err := updateUploadQueue(func(items []QueuedUpload) []QueuedUpload {
+		return append(items, item)
+	})
+	if err != nil {
+		log.Warnf("⚠️ Failed to queue %s upload for retry: %v", item.describe(), err)
+		return false
+	}
+
+	log.Infof("📥 Queued %s upload for retry (next attempt after %s)", item.describe(), item.NextAttempt.Format(time.RFC3339))
+	return true
+}
+
+// describe returns a short human readable description of a queued upload
+func (item QueuedUpload) describe() string {
+	description := fmt.Sprintf("%s for %s", item.historyFileType(), item.ReleaseName)
+	if item.Uploader != "" {
+		description += fmt.Sprintf(" (%s)", item.Uploader)
+	}
+	return description
+}
+
+// targetName returns the uploader name for log messages
+func (item QueuedUpload) targetName() string {
+	if item.Uploader == "" {
+		return "CrowdNFO"
+	}
+	return item.Uploader
+}
+
+// historyFileType returns the file type under which the upload is recorded in the history
+func (item QueuedUpload) historyFileType() string {
+	if item.Kind == "filelist" {
+		return "FileList"
+	}
+	return item.FileType
+}
+
+// send replays a queued upload against its uploader, duplicate is set if the uploader already has it
+func (item QueuedUpload) send(config *Config) (duplicate bool, err error) {
+	uploader := findUploader(config, item.Uploader)
+	if uploader == nil {
+		return false, fmt.Errorf("uploader '%s' is no longer configured", item.Uploader)
+	}
+	_, err = item.sendTo(logger, uploader)
+	return err != nil && uploader.IsDuplicate(err), err
+}
+
+// sendTo sends the NFO, MediaInfo or file list to the uploader
+func (item QueuedUpload) sendTo(log *Logger, uploader Uploader) (UploadResult, error) {
+	switch item.Kind {
+	case "file":
+		return uploader.UploadFile(log, FileUpload{
+			ReleaseName:      item.ReleaseName,
+			FileType:         item.FileType,
+			OriginalFileName: item.OriginalFileName,
+			Data:             item.Data,
+			Hash:             item.Hash,
+			Category:         item.Category,
+			ArchiveDir:       item.ArchiveDir,
+		})
+	case "filelist":
+		if item.FileList == nil {
+			return UploadResult{}, fmt.Errorf("queue entry has no file list")
+		}
+		return uploader.UploadFileList(log, *item.FileList)
+	default:
+		return UploadResult{}, fmt.Errorf("unknown queue entry kind '%s'", item.Kind)
+	}
+}
+
+// retryUploadQueue replays all due queue entries and reschedules the ones that fail again
+func retryUploadQueue(config *Config) error {
+	items, err := loadUploadQueue()
+	if err != nil {
+		return err
+	}
+
+	if len(items) == 0 {
+		logInfof("ℹ️ Upload queue is empty")
+		return nil
+	}
+
+	logInfof("🔁 Upload queue contains %d entries", len(items))
+
+	// Uploads run without holding the lock, results are merged back afterwards
+	now := time.Now()
+	maxAttempts := getQueueMaxAttempts(config)
+	done := make(map[string]bool)
+	updated := make(map[string]QueuedUpload)
+	successCount, failedCount, pendingCount := 0, 0, 0
+
+	for _, item := range items {
+		if item.NextAttempt.After(now) {
+			pendingCount++
+			continue
+		}
+
+		logInfof("⏳ Retrying %s (attempt %d/%d)", item.describe(), item.Attempts+1, maxAttempts)
+
+		duplicate, err := item.send(config)
+		if err == nil || duplicate {
+			if err == nil {
+				logInfof("✅ %s uploaded successfully", item.describe())
+			} else {
+				logInfof("⏭️ %s was already submitted to %s", item.describe(), item.targetName())
+			}
+			recordUpload(logger, config, item.Uploader, item.ReleaseName, item.historyFileType(), item.Hash)
+			done[item.ID] = true
+			successCount++
+			continue
+		}
+
+		failedCount++
+		item.Attempts++
+		item.LastError = err.Error()
+
+		if !isRetryableUploadError(err) {
+			logErrorf("❌ %s upload failed permanently, removing from queue: %v", item.describe(), err)
+			done[item.ID] = true
+			continue
+		}
+
+		if item.Attempts >= maxAttempts {
+			logErrorf("❌ %s upload failed after %d attempts, removing from queue: %v", item.describe(), item.Attempts, err)
+			done[item.ID] = true
+			continue
+		}
+
+		item.NextAttempt = time.Now().Add(getQueueRetryDelay(item.Attempts))
+		logWarnf("⚠️ %s upload failed again, next attempt after %s: %v", item.describe(), item.NextAttempt.Format(time.RFC3339), err)
+		updated[item.ID] = item
+	}
+
+	// Dry-run uploads always "succeed", so the queue must stay untouched
+	if config.DryRun {
+		logInfof("🧪 DRY RUN: Upload queue not modified")
+		return nil
+	}
+
+	err = updateUploadQueue(func(current []QueuedUpload) []QueuedUpload {
+		remaining := make([]QueuedUpload, 0, len(current))
+		for _, item := range current {
+			if done[item.ID] {
+				continue
+			}
+			if updatedItem, ok := updated[item.ID]; ok {
+				item = updatedItem
+			}
+			remaining = append(remaining, item)
+		}
+		return remaining
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save upload queue: %v", err)
+	}
+
+	logInfof("✅ Upload queue processed: %d successful, %d failed, %d not yet due", successCount, failedCount, pendingCount)
+	return nil
+}
@@ -0,0 +1,516 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ReleaseNameCheck records how the release name was validated and normalized
+type ReleaseNameCheck struct {
+	Original string   `json:"original"`
+	Name     string   `json:"name"`
+	Source   string   `json:"source"` // torrent, folder or nfo
+	Valid    bool     `json:"valid"`
+	Issues   []string `json:"issues,omitempty"`
+}
+
+var (
+	// Website prefixes and tracker tags that P2P uploaders add to release names
+	builtInJunkPatterns = []string{
+		`(?i)^\s*\[\s*www\.[^\]]+\]\s*[-_.]*\s*`,
+		`(?i)^\s*www\.[a-z0-9-]+\.[a-z]{2,}\s*[-_]+\s*`,
+		`(?i)^\s*\[[^\]\s]+\.(com|org|net|to|se|re|io|me|cc|xyz|info)\]\s*[-_.]*\s*`,
+		`(?i)\s*\[(eztv|ettv|rartv|rarbg|tgx|[^\]\s]+\.(com|org|net|to|se|re|io|me|cc|xyz|info))\]\s*$`,
+	}
+
+	// Scene names consist of word characters separated by dots, underscores or dashes and end with -GROUP
+	sceneNameCharacters = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._()&+'-]*$`)
+	sceneGroupSuffix    = regexp.MustCompile(`-[A-Za-z0-9_]+$`)
+	sceneNameSeparators = regexp.MustCompile(`[._-]+`)
+
+	// defaultJunkPatterns are the compiled built-in patterns, used if the config was not loaded via loadConfig
+	defaultJunkPatterns, _ = compileJunkPatterns(nil)
+)
+
+// compileJunkPatterns returns the built-in and configured junk patterns
+func compileJunkPatterns(configPatterns []string) ([]*regexp.Regexp, error) {
+	patterns := make([]*regexp.Regexp, 0, len(builtInJunkPatterns)+len(configPatterns))
+	for _, pattern := range append(append([]string{}, builtInJunkPatterns...), configPatterns...) {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid junk pattern '%s': %v", pattern, err)
+		}
+		patterns = append(patterns, regex)
+	}
+	return patterns, nil
+}
+
+// stripJunk removes website prefixes and tracker tags from a release name
+func stripJunk(config *Config, name string) string {
+	patterns := config.junkPatterns
+	if patterns == nil {
+		patterns = defaultJunkPatterns
+	}
+	for _, pattern := range patterns {
+		name = pattern.ReplaceAllString(name, "")
+	}
+	return strings.Trim(strings.TrimSpace(name), ".-_")
+}
+
+// validateReleaseName checks if the name looks like a scene-style release name and lists what's wrong with it
+func validateReleaseName(name string) []string {
+	// Single file torrents carry the file extension
+	if extensionContentKind(name) != "" {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+
+	var issues []string
+	if strings.ContainsAny(name, " \t") {
+		issues = append(issues, "contains spaces")
+	}
+	if !sceneNameCharacters.MatchString(strings.ReplaceAll(name, " ", ".")) {
+		issues = append(issues, "contains invalid characters")
+	}
+	if !sceneGroupSuffix.MatchString(name) {
+		issues = append(issues, "no -GROUP suffix")
+	}
+	if len(sceneNameSeparators.Split(name, -1)) < 3 {
+		issues = append(issues, "too few name parts")
+	}
+	return issues
+}
+
+// releaseNameCandidates lists the names a release could be known by, in order of preference
+func releaseNameCandidates(torrentName, contentPath string) []ReleaseNameCheck {
+	candidates := []ReleaseNameCheck{{Original: torrentName, Source: "torrent"}}
+
+	// Folder derived name, the content folder keeps its name if only the torrent was renamed
+	contentDir := contentPath
+	if info, err := os.Stat(contentPath); err == nil {
+		candidates = append(candidates, ReleaseNameCheck{Original: filepath.Base(contentPath), Source: "folder"})
+		if !info.IsDir() {
+			contentDir = filepath.Dir(contentPath)
+		}
+	}
+
+	// NFO derived name, scene NFOs are often named after the release
+	if contentDir != "" {
+		if nfoFile, err := findNFOFile(contentDir); err == nil {
+			nfoName := strings.TrimSuffix(filepath.Base(nfoFile), filepath.Ext(nfoFile))
+			candidates = append(candidates, ReleaseNameCheck{Original: nfoName, Source: "nfo"})
+		}
+	}
+
+	return candidates
+}
+
+// checkReleaseName strips junk from the torrent name, validates it and falls back to folder or NFO derived names
+// The returned check is invalid if no candidate looks like a real release name
+func checkReleaseName(log *Logger, config *Config, torrentName, contentPath string) ReleaseNameCheck {
+	candidates := []ReleaseNameCheck{{Original: torrentName, Source: "torrent"}}
+	if config.ReleaseNames.Fallback {
+		candidates = releaseNameCandidates(torrentName, contentPath)
+	}
+
+	for i := range candidates {
+		candidate := &candidates[i]
+		candidate.Name = candidate.Original
+		if config.ReleaseNames.StripJunk {
+			candidate.Name = stripJunk(config, candidate.Name)
+		}
+		if config.ReleaseNames.ReplaceSpaces {
+			candidate.Name = strings.Join(strings.Fields(candidate.Name), ".")
+		}
+		candidate.Issues = validateReleaseName(candidate.Name)
+		candidate.Valid = len(candidate.Issues) == 0
+
+		if candidate.Valid {
+			if i > 0 {
+				log.Warnf("⚠️ Torrent name '%s' is not a valid release name (%s)", candidates[0].Name, strings.Join(candidates[0].Issues, ", "))
+			}
+			if candidate.Name != torrentName {
+				log.Infof("🧹 Release name normalized (%s): %s -> %s", candidate.Source, torrentName, candidate.Name)
+			}
+			return *candidate
+		}
+		log.Debugf("   Release name candidate '%s' (%s) rejected: %s", candidate.Name, candidate.Source, strings.Join(candidate.Issues, ", "))
+	}
+
+	// Nothing valid, keep the (cleaned) torrent name
+	log.Warnf("⚠️ Release name doesn't look like a scene release: %s (%s)", candidates[0].Name, strings.Join(candidates[0].Issues, ", "))
+	return candidates[0]
+}
+
+// ReleaseInfo is the structured metadata parsed from a scene-style release name
+type ReleaseInfo struct {
+	Title      string   `json:"title"`
+	Year       int      `json:"year,omitempty"`
+	Season     int      `json:"season,omitempty"`
+	Seasons    []int    `json:"seasons,omitempty"` // Multi-season packs, e.g. S01-S05
+	Complete   bool     `json:"complete_series,omitempty"`
+	Episodes   []int    `json:"episodes,omitempty"`
+	Absolute   int      `json:"absolute_episode,omitempty"` // Anime absolute numbering, e.g. "Show - 137"
+	Special    string   `json:"special,omitempty"`          // Special episode without number scheme, e.g. "OVA" or "SP01"
+	Date       string   `json:"date,omitempty"`             // Air date of daily shows, normalized to yyyy-mm-dd
+	Resolution string   `json:"resolution,omitempty"`
+	Source     string   `json:"source,omitempty"`
+	Codec      string   `json:"codec,omitempty"`
+	Audio      []string `json:"audio,omitempty"`
+	Languages  []string `json:"languages,omitempty"`
+	Group      string   `json:"group,omitempty"`
+
+	seasonToken  string // Season as written in the name, e.g. "S01", "S2024" or "S01-S05"
+	episodeWidth int    // Digits of the episode numbers as written in the name
+	seasonAt     int    // Offset in the name where a season token belongs (after title and year)
+}
+
+var (
+	releaseTokenPattern      = regexp.MustCompile(`[^.\s_]+`)
+	releaseMusicTokenPattern = regexp.MustCompile(`[^-]+`)
+	releaseGroupPattern      = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+	releaseSeasonPattern     = regexp.MustCompile(`(?i)^S(\d{2,4})$`)
+	releaseSeasonRange       = regexp.MustCompile(`(?i)^S(\d{2,4})-S?(\d{2,4})$`)
+	releaseEpisodeRunPattern = regexp.MustCompile(`(?i)^(?:S(\d{1,4}))?(E\d{1,4}(?:-?E\d{1,4}|-\d{1,4})*)$`)
+	releaseEpisodePattern    = regexp.MustCompile(`(?i)(-?)E?(\d{1,4})`)
+	releaseSpecialPattern    = regexp.MustCompile(`(?i)^(OVA|OAD|ONA|SP|SPECIAL)(\d{1,3})?$`)
+	releaseDatePattern       = regexp.MustCompile(`^(\d{2}|\d{4})-(\d{2})-(\d{2}|\d{4})$`)
+	releaseYearPattern       = regexp.MustCompile(`^(19|20)\d{2}$`)
+	releaseResolutionPattern = regexp.MustCompile(`(?i)^(\d{3,4}[pi]|4k)$`)
+	releaseAudioPattern      = regexp.MustCompile(`(?i)^(DDP|DD\+|DD|EAC3|E-AC3|AC3|AAC|DTS-HD|DTS-X|DTSHD|DTS|TRUEHD|ATMOS|FLAC|MP3|OPUS|LPCM|PCM)(\d)?$`)
+
+	// Fansub naming: [Group] Show Name - 137v2 [1080p] or [Group] Show Name - OVA2 (BD 1080p)
+	releaseAnimePattern     = regexp.MustCompile(`^(?:\[([^\]]+)\]\s*)?(.+?)\s+-\s+(\d{1,4}|(?i:OVA|OAD|ONA|SP|SPECIAL)\s?\d{0,3})(?:v\d)?((?:\s+|\s*[\[(]).*)?$`)
+	releaseAnimeYearPattern = regexp.MustCompile(`\s*\(((?:19|20)\d{2})\)$`)
+
+	releaseSources = map[string]string{
+		"BLURAY": "BluRay", "BDRIP": "BDRip", "BRRIP": "BRRip", "REMUX": "REMUX",
+		"WEB": "WEB", "WEB-DL": "WEB-DL", "WEBDL": "WEB-DL", "WEBRIP": "WEBRip",
+		"HDTV": "HDTV", "PDTV": "PDTV", "SDTV": "SDTV", "HDRIP": "HDRip",
+		"DVD": "DVD", "DVDR": "DVDR", "DVDRIP": "DVDRip",
+	}
+	releaseCodecs = map[string]string{
+		"X264": "x264", "X265": "x265", "H264": "H.264", "H265": "H.265", "HEVC": "HEVC", "AVC": "AVC",
+		"XVID": "XviD", "DIVX": "DivX", "AV1": "AV1", "VP9": "VP9", "MPEG2": "MPEG2", "VC1": "VC-1", "VC-1": "VC-1",
+	}
+	releaseLanguages = map[string]bool{
+		"GERMAN": true, "ENGLISH": true, "FRENCH": true, "SPANISH": true, "ITALIAN": true, "DUTCH": true,
+		"NORDIC": true, "JAPANESE": true, "MULTI": true, "DL": true, "DUBBED": true, "SUBBED": true,
+	}
+
+	// Dashes within these tags don't separate the group
+	releaseDashTags = map[string]bool{"DL": true, "RIP": true, "HD": true, "X": true, "1": true}
+)
+
+// parseReleaseName splits a release name (or file name) into its metadata fields
+func parseReleaseName(name string) ReleaseInfo {
+	var info ReleaseInfo
+
+	body := strings.TrimSpace(name)
+	if extensionContentKind(body) != "" {
+		body = strings.TrimSuffix(body, filepath.Ext(body))
+	}
+
+	if info, ok := parseAnimeName(body); ok {
+		return info
+	}
+
+	// The group follows the last dash, unless the dash belongs to a tag like WEB-DL or an episode range
+	if i := strings.LastIndex(body, "-"); i > 0 {
+		group := body[i+1:]
+		if releaseGroupPattern.MatchString(group) && !releaseDashTags[strings.ToUpper(group)] && !releaseEpisodeRunPattern.MatchString(group) &&
+			!releaseSeasonPattern.MatchString(group) && !isDigits(group) {
+			info.Group = group
+			body = body[:i]
+		}
+	}
+
+	spans := releaseTokenPattern.FindAllStringIndex(body, -1)
+	if musicSpans := releaseMusicTokenPattern.FindAllStringIndex(body, -1); !strings.ContainsAny(body, ". \t") && len(musicSpans) > len(spans) {
+		// Music releases are separated by dashes, e.g. Artist-Album-WEB-2024 or Artist-Album-(Deluxe_Edition)-WEB-2024
+		spans = musicSpans
+	}
+	tokens := make([]string, len(spans))
+	for i, span := range spans {
+		tokens[i] = body[span[0]:span[1]]
+	}
+
+	var title []string
+	titleDone := false
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		upper := strings.ToUpper(token)
+		next := ""
+		if i+1 < len(tokens) {
+			next = tokens[i+1]
+		}
+
+		date, dateTokens := "", 0
+		if info.Date == "" {
+			date, dateTokens = releaseDateAt(tokens, i)
+		}
+
+		marker := true
+		switch {
+		case info.seasonToken == "" && releaseSeasonPattern.MatchString(token):
+			info.seasonToken = token
+			info.Season = atoi(releaseSeasonPattern.FindStringSubmatch(token)[1])
+		case info.seasonToken == "" && releaseSeasonRange.MatchString(token):
+			match := releaseSeasonRange.FindStringSubmatch(token)
+			info.seasonToken = token
+			info.Season = atoi(match[1])
+			for season := info.Season; season <= atoi(match[2]); season++ {
+				info.Seasons = append(info.Seasons, season)
+			}
+		case len(title) > 0 && upper == "COMPLETE" && (strings.EqualFold(next, "SERIES") || strings.EqualFold(next, "SEASONS")):
+			info.Complete = true
+			i++
+		case len(info.Episodes) == 0 && releaseEpisodeRunPattern.MatchString(token):
+			match := releaseEpisodeRunPattern.FindStringSubmatch(token)
+			if match[1] != "" {
+				info.seasonToken = token[:len(match[1])+1]
+				info.Season = atoi(match[1])
+			}
+			info.Episodes, info.episodeWidth = parseEpisodeRun(match[2])
+		case info.Special == "" && len(title) > 0 && isSpecialToken(token):
+			info.Special = strings.ToUpper(token)
+		case date != "":
+			info.Date = date
+			i += dateTokens - 1
+		case info.Year == 0 && len(title) > 0 && releaseYearPattern.MatchString(strings.Trim(token, "()")) && !releaseYearPattern.MatchString(next):
+			info.Year = atoi(strings.Trim(token, "()"))
+			if !titleDone {
+				info.seasonAt = spans[i][1]
+			}
+			if !titleDone && tokens[i-1] == "-" && next == "-" && i+2 < len(tokens) {
+				// A year between dashes separates artist and album, e.g. Artist - 1999 - Album
+				title = append(title, "-")
+				i++
+				continue
+			}
+		case info.Resolution == "" && releaseResolutionPattern.MatchString(token):
+			info.Resolution = strings.ToLower(token)
+		case info.Source == "" && releaseSources[upper] != "":
+			info.Source = releaseSources[upper]
+		case info.Codec == "" && releaseCodecs[upper] != "":
+			info.Codec = releaseCodecs[upper]
+		case info.Codec == "" && upper == "H" && (next == "264" || next == "265"):
+			info.Codec = "H." + next
+			i++
+		case releaseAudioPattern.MatchString(token):
+			audio := token
+			// Channels are split by the dot separator, e.g. DD5.1 or TrueHD.7.1
+			if isDigits(token[len(token)-1:]) && len(next) == 1 && isDigits(next) {
+				audio += "." + next
+				i++
+			} else if i+2 < len(tokens) && len(next) == 1 && isDigits(next) && len(tokens[i+2]) == 1 && isDigits(tokens[i+2]) {
+				audio += next + "." + tokens[i+2]
+				i += 2
+			}
+			info.Audio = append(info.Audio, audio)
+		case len(title) > 0 && releaseLanguages[upper]:
+			info.Languages = append(info.Languages, upper)
+		default:
+			marker = false
+		}
+
+		if marker {
+			titleDone = true
+		} else if !titleDone && strings.Trim(token, "-") != "" {
+			// Dashes between words are separators, e.g. Show - Title
+			title = append(title, token)
+			info.seasonAt = spans[i][1]
+		}
+	}
+
+	info.Title = strings.Join(title, " ")
+	return info
+}
+
+// parseAnimeName parses fansub names with absolute episode numbers or specials after a dash
+// Four-digit numbers that look like a year are left to the scene parser (e.g. Artist - 1999 - Album)
+func parseAnimeName(body string) (ReleaseInfo, bool) {
+	var info ReleaseInfo
+	match := releaseAnimePattern.FindStringSubmatch(body)
+	if match == nil || (len(match[3]) == 4 && releaseYearPattern.MatchString(match[3])) {
+		return info, false
+	}
+
+	info.Group = match[1]
+	info.Title = strings.TrimSpace(match[2])
+	if year := releaseAnimeYearPattern.FindStringSubmatch(info.Title); year != nil {
+		info.Year = atoi(year[1])
+		info.Title = strings.TrimSpace(strings.TrimSuffix(info.Title, year[0]))
+	}
+	if isDigits(match[3]) {
+		info.Absolute = atoi(match[3])
+		info.episodeWidth = len(match[3])
+	} else {
+		info.Special = strings.ToUpper(strings.ReplaceAll(match[3], " ", ""))
+	}
+
+	// Tags like [1080p] or (BD x265 FLAC) describe the file
+	tags := parseReleaseName(strings.NewReplacer("[", " ", "]", " ", "(", " ", ")", " ").Replace(match[4]))
+	info.Resolution, info.Source, info.Codec = tags.Resolution, tags.Source, tags.Codec
+	info.Audio, info.Languages = tags.Audio, tags.Languages
+	return info, true
+}
+
+// isSpecialToken checks for special episode tokens in scene names
+// SP and SPECIAL are common words and only count with a number, Show.S01.SPECIAL is a season pack
+func isSpecialToken(token string) bool {
+	match := releaseSpecialPattern.FindStringSubmatch(token)
+	if match == nil {
+		return false
+	}
+	switch strings.ToUpper(match[1]) {
+	case "SP", "SPECIAL":
+		return match[2] != ""
+	}
+	return true
+}
+
+// releaseDateAt checks for an air date starting at tokens[i], either as one token (2024-05-17, 17-05-2024)
+// or split by the separator (2024.05.17, 17.05.2024). Returns the date as yyyy-mm-dd and the number of tokens it spans
+func releaseDateAt(tokens []string, i int) (string, int) {
+	if match := releaseDatePattern.FindStringSubmatch(tokens[i]); match != nil {
+		if date := normalizeDate(match[1], match[2], match[3]); date != "" {
+			return date, 1
+		}
+	}
+	if i+2 < len(tokens) {
+		if date := normalizeDate(tokens[i], tokens[i+1], tokens[i+2]); date != "" {
+			return date, 3
+		}
+	}
+	return "", 0
+}
+
+// normalizeDate converts year-month-day or day-month-year (German TV) to yyyy-mm-dd, empty if it's no valid date
+func normalizeDate(first, month, last string) string {
+	if len(month) != 2 || !isDigits(first+month+last) {
+		return ""
+	}
+
+	var year, day string
+	switch {
+	case len(first) == 4 && len(last) == 2:
+		year, day = first, last
+	case len(first) == 2 && len(last) == 4:
+		year, day = last, first
+	default:
+		return ""
+	}
+	if !releaseYearPattern.MatchString(year) {
+		return ""
+	}
+
+	date := year + "-" + month + "-" + day
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return ""
+	}
+	return date
+}
+
+// parseEpisodeRun parses E01, E01E02, E01-E03 or E01-03 into episode numbers, ranges are expanded
+func parseEpisodeRun(run string) ([]int, int) {
+	var episodes []int
+	width := 0
+	for _, match := range releaseEpisodePattern.FindAllStringSubmatch(run, -1) {
+		number := atoi(match[2])
+		if len(match[2]) > width {
+			width = len(match[2])
+		}
+		if match[1] == "-" && len(episodes) > 0 && number > episodes[len(episodes)-1] {
+			for n := episodes[len(episodes)-1] + 1; n <= number; n++ {
+				episodes = append(episodes, n)
+			}
+			continue
+		}
+		episodes = append(episodes, number)
+	}
+
+	// E02E01 and E01E01E02 describe the same file as E01E02
+	sort.Ints(episodes)
+	unique := episodes[:0]
+	for i, episode := range episodes {
+		if i == 0 || episode != episodes[i-1] {
+			unique = append(unique, episode)
+		}
+	}
+	return unique, width
+}
+
+// IsEpisode reports whether the name identifies a single episode (or multi-episode file), a special or a daily show date
+func (r ReleaseInfo) IsEpisode() bool {
+	return len(r.Episodes) > 0 || r.Absolute > 0 || r.Special != "" || r.Date != ""
+}
+
+// IsSpecial reports whether the name is a special episode (S00Exx, OVA, SP01)
+func (r ReleaseInfo) IsSpecial() bool {
+	return r.Special != "" || (r.seasonToken != "" && r.Season == 0 && len(r.Episodes) > 0)
+}
+
+// IsSeasonPack reports whether the name has a season (or several, or is a complete series) but no episode
+func (r ReleaseInfo) IsSeasonPack() bool {
+	return (r.seasonToken != "" || r.Complete) && !r.IsEpisode()
+}
+
+// IsMultiSeason reports whether the name covers several seasons or the complete series
+func (r ReleaseInfo) IsMultiSeason() bool {
+	return len(r.Seasons) > 1 || r.Complete
+}
+
+// episodeID returns the episodes formatted with the digits used in the name, e.g. "E01" or "E01E02E03" for multi-episode files
+// Absolute numbers are formatted like episodes ("E137"), specials return their token ("OVA2")
+func (r ReleaseInfo) episodeID() string {
+	if r.Special != "" {
+		return r.Special
+	}
+	episodes := r.Episodes
+	if r.Absolute > 0 {
+		episodes = []int{r.Absolute}
+	}
+
+	width := r.episodeWidth
+	if width < 2 {
+		width = 2
+	}
+
+	var id strings.Builder
+	for _, episode := range episodes {
+		fmt.Fprintf(&id, "E%0*d", width, episode)
+	}
+	return id.String()
+}
+
+// isFirstEpisode reports whether the name is episode 1 (or a multi-episode file starting with it)
+func (r ReleaseInfo) isFirstEpisode() bool {
+	return r.Absolute == 1 || (r.Absolute == 0 && len(r.Episodes) > 0 && r.Episodes[0] == 1)
+}
+
+// isDigits checks if the string consists of ASCII digits only
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// atoi converts a string of digits matched by a regex
+func atoi(s string) int {
+	n := 0
+	for _, r := range s {
+		n = n*10 + int(r-'0')
+	}
+	return n
+}
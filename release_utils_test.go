@@ -0,0 +1,282 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseReleaseName(t *testing.T) {
+	tests := []struct {
+		name      string
+		want      ReleaseInfo
+		pack      bool
+		episodeID string
+	}{
+		// Season packs
+		{
+			name: "Breaking.Bad.S01.1080p.BluRay.x264-ROVERS",
+			want: ReleaseInfo{Title: "Breaking Bad", Season: 1, Resolution: "1080p", Source: "BluRay", Codec: "x264", Group: "ROVERS"},
+			pack: true,
+		},
+		{
+			name: "The.Office.US.S05.720p.WEB-DL.DD5.1.H.264-NTb",
+			want: ReleaseInfo{Title: "The Office US", Season: 5, Resolution: "720p", Source: "WEB-DL", Codec: "H.264", Audio: []string{"DD5.1"}, Group: "NTb"},
+			pack: true,
+		},
+		{
+			name: "Dark.S03.GERMAN.DL.1080p.WEB.x264-WvF",
+			want: ReleaseInfo{Title: "Dark", Season: 3, Resolution: "1080p", Source: "WEB", Codec: "x264", Languages: []string{"GERMAN", "DL"}, Group: "WvF"},
+			pack: true,
+		},
+		{
+			name: "Show.S2024.1080p.WEB.h264-GRP",
+			want: ReleaseInfo{Title: "Show", Season: 2024, Resolution: "1080p", Source: "WEB", Codec: "H.264", Group: "GRP"},
+			pack: true,
+		},
+		{
+			// SPECIAL without a number is no episode, e.g. a pack with the specials of a season
+			name: "Show.Name.S01.SPECIAL.1080p.WEB.h264-GRP",
+			want: ReleaseInfo{Title: "Show Name", Season: 1, Resolution: "1080p", Source: "WEB", Codec: "H.264", Group: "GRP"},
+			pack: true,
+		},
+
+		// Single and multi-episode releases
+		{
+			name:      "Show.Name.S01E01.1080p.WEB.h264-GRP",
+			want:      ReleaseInfo{Title: "Show Name", Season: 1, Episodes: []int{1}, Resolution: "1080p", Source: "WEB", Codec: "H.264", Group: "GRP"},
+			episodeID: "E01",
+		},
+		{
+			name:      "Show.Name.S01E01E02.1080p.WEB.h264-GRP",
+			want:      ReleaseInfo{Title: "Show Name", Season: 1, Episodes: []int{1, 2}, Resolution: "1080p", Source: "WEB", Codec: "H.264", Group: "GRP"},
+			episodeID: "E01E02",
+		},
+		{
+			name:      "Show.Name.S02E02E01.720p.HDTV.x264-GRP",
+			want:      ReleaseInfo{Title: "Show Name", Season: 2, Episodes: []int{1, 2}, Resolution: "720p", Source: "HDTV", Codec: "x264", Group: "GRP"},
+			episodeID: "E01E02",
+		},
+		{
+			name:      "Show.Name.S01E01-E03.720p.HDTV.x264-GRP",
+			want:      ReleaseInfo{Title: "Show Name", Season: 1, Episodes: []int{1, 2, 3}, Resolution: "720p", Source: "HDTV", Codec: "x264", Group: "GRP"},
+			episodeID: "E01E02E03",
+		},
+		{
+			name:      "Show.Name.S01E01-03.720p.HDTV.x264-GRP",
+			want:      ReleaseInfo{Title: "Show Name", Season: 1, Episodes: []int{1, 2, 3}, Resolution: "720p", Source: "HDTV", Codec: "x264", Group: "GRP"},
+			episodeID: "E01E02E03",
+		},
+		{
+			name:      "Show.2024.S01E01.1080p.WEB.h264-GRP",
+			want:      ReleaseInfo{Title: "Show", Year: 2024, Season: 1, Episodes: []int{1}, Resolution: "1080p", Source: "WEB", Codec: "H.264", Group: "GRP"},
+			episodeID: "E01",
+		},
+		{
+			name:      "Show.S2024E100.1080p.WEB.h264-GRP",
+			want:      ReleaseInfo{Title: "Show", Season: 2024, Episodes: []int{100}, Resolution: "1080p", Source: "WEB", Codec: "H.264", Group: "GRP"},
+			episodeID: "E100",
+		},
+		{
+			name:      "Show.Name.S03E10.mkv",
+			want:      ReleaseInfo{Title: "Show Name", Season: 3, Episodes: []int{10}},
+			episodeID: "E10",
+		},
+
+		// Multi-season and complete series packs
+		{
+			name: "Show.Name.S01-S05.COMPLETE.1080p.BluRay.x264-GRP",
+			want: ReleaseInfo{Title: "Show Name", Season: 1, Seasons: []int{1, 2, 3, 4, 5}, Resolution: "1080p", Source: "BluRay", Codec: "x264", Group: "GRP"},
+			pack: true,
+		},
+		{
+			name: "Show.Name.S01-05.1080p.BluRay.x264-GRP",
+			want: ReleaseInfo{Title: "Show Name", Season: 1, Seasons: []int{1, 2, 3, 4, 5}, Resolution: "1080p", Source: "BluRay", Codec: "x264", Group: "GRP"},
+			pack: true,
+		},
+		{
+			name: "Show.Name.Complete.Series.1080p.WEB-DL.DDP5.1.H.264-GRP",
+			want: ReleaseInfo{Title: "Show Name", Complete: true, Resolution: "1080p", Source: "WEB-DL", Codec: "H.264", Audio: []string{"DDP5.1"}, Group: "GRP"},
+			pack: true,
+		},
+		{
+			name: "Show.Name.COMPLETE.SEASONS.720p.BluRay.x264-GRP",
+			want: ReleaseInfo{Title: "Show Name", Complete: true, Resolution: "720p", Source: "BluRay", Codec: "x264", Group: "GRP"},
+			pack: true,
+		},
+
+		// Daily shows
+		{
+			name: "The.Daily.Show.2024.05.17.Guest.Name.720p.WEB.h264-GRP",
+			want: ReleaseInfo{Title: "The Daily Show", Date: "2024-05-17", Resolution: "720p", Source: "WEB", Codec: "H.264", Group: "GRP"},
+		},
+		{
+			name: "The.Daily.Show.2024-05-17.720p.WEB.h264-GRP",
+			want: ReleaseInfo{Title: "The Daily Show", Date: "2024-05-17", Resolution: "720p", Source: "WEB", Codec: "H.264", Group: "GRP"},
+		},
+		{
+			name: "Tagesschau.17.05.2024.GERMAN.720p.HDTV.x264-GRP",
+			want: ReleaseInfo{Title: "Tagesschau", Date: "2024-05-17", Resolution: "720p", Source: "HDTV", Codec: "x264", Languages: []string{"GERMAN"}, Group: "GRP"},
+		},
+
+		// Anime absolute numbering and specials
+		{
+			name:      "[SubsPlease] One Piece - 1071 (1080p) [ABCD1234]",
+			want:      ReleaseInfo{Title: "One Piece", Absolute: 1071, Resolution: "1080p", Group: "SubsPlease"},
+			episodeID: "E1071",
+		},
+		{
+			name:      "[Erai-raws] Show Name - 137v2 [1080p][Multiple Subtitle]",
+			want:      ReleaseInfo{Title: "Show Name", Absolute: 137, Resolution: "1080p", Group: "Erai-raws"},
+			episodeID: "E137",
+		},
+		{
+			name:      "[Group] Show Name (2019) - 01 [1080p]",
+			want:      ReleaseInfo{Title: "Show Name", Year: 2019, Absolute: 1, Resolution: "1080p", Group: "Group"},
+			episodeID: "E01",
+		},
+		{
+			name:      "[Group] Show Name - OVA2 (BD 1080p)",
+			want:      ReleaseInfo{Title: "Show Name", Special: "OVA2", Resolution: "1080p", Group: "Group"},
+			episodeID: "OVA2",
+		},
+		{
+			name:      "Show.Name.E1071.1080p.WEB.h264-GRP",
+			want:      ReleaseInfo{Title: "Show Name", Episodes: []int{1071}, Resolution: "1080p", Source: "WEB", Codec: "H.264", Group: "GRP"},
+			episodeID: "E1071",
+		},
+		{
+			name:      "Show.Name.S00E05.1080p.WEB.h264-GRP",
+			want:      ReleaseInfo{Title: "Show Name", Episodes: []int{5}, Resolution: "1080p", Source: "WEB", Codec: "H.264", Group: "GRP"},
+			episodeID: "E05",
+		},
+		{
+			name:      "Show.Name.S01.SP01.1080p.WEB.h264-GRP",
+			want:      ReleaseInfo{Title: "Show Name", Season: 1, Special: "SP01", Resolution: "1080p", Source: "WEB", Codec: "H.264", Group: "GRP"},
+			episodeID: "SP01",
+		},
+		{
+			name:      "Show.Name.OVA.1080p.BluRay.x264-GRP",
+			want:      ReleaseInfo{Title: "Show Name", Special: "OVA", Resolution: "1080p", Source: "BluRay", Codec: "x264", Group: "GRP"},
+			episodeID: "OVA",
+		},
+
+		// Movies
+		{
+			name: "Movie.Title.2020.German.DL.1080p.BluRay.x264-GRP",
+			want: ReleaseInfo{Title: "Movie Title", Year: 2020, Resolution: "1080p", Source: "BluRay", Codec: "x264", Languages: []string{"GERMAN", "DL"}, Group: "GRP"},
+		},
+		{
+			name: "Blade.Runner.2049.2017.2160p.UHD.BluRay.REMUX.HDR.HEVC.TrueHD.7.1.Atmos-GRP",
+			want: ReleaseInfo{Title: "Blade Runner 2049", Year: 2017, Resolution: "2160p", Source: "BluRay", Codec: "HEVC", Audio: []string{"TrueHD7.1", "Atmos"}, Group: "GRP"},
+		},
+		{
+			name: "1917.2019.1080p.BluRay.x264-GRP",
+			want: ReleaseInfo{Title: "1917", Year: 2019, Resolution: "1080p", Source: "BluRay", Codec: "x264", Group: "GRP"},
+		},
+		{
+			name: "2001.A.Space.Odyssey.1968.1080p.BluRay.x264-GRP",
+			want: ReleaseInfo{Title: "2001 A Space Odyssey", Year: 1968, Resolution: "1080p", Source: "BluRay", Codec: "x264", Group: "GRP"},
+		},
+		{
+			name: "Movie.Title.2019.1080p.WEBRip.x265.AAC-GRP",
+			want: ReleaseInfo{Title: "Movie Title", Year: 2019, Resolution: "1080p", Source: "WEBRip", Codec: "x265", Audio: []string{"AAC"}, Group: "GRP"},
+		},
+		{
+			name: "Movie.Title.2019.720p.HDTV.XviD.MP3-GRP",
+			want: ReleaseInfo{Title: "Movie Title", Year: 2019, Resolution: "720p", Source: "HDTV", Codec: "XviD", Audio: []string{"MP3"}, Group: "GRP"},
+		},
+		{
+			name: "Movie_Title_2019_DVDRip_XviD-GRP",
+			want: ReleaseInfo{Title: "Movie Title", Year: 2019, Source: "DVDRip", Codec: "XviD", Group: "GRP"},
+		},
+		{
+			name: "Movie Title (2019) 1080p",
+			want: ReleaseInfo{Title: "Movie Title", Year: 2019, Resolution: "1080p"},
+		},
+
+		// Music
+		{
+			name: "Artist-Album-WEB-2024-GRP",
+			want: ReleaseInfo{Title: "Artist Album", Year: 2024, Source: "WEB", Group: "GRP"},
+		},
+		{
+			name: "Artist-Album-(Deluxe_Edition)-WEB-FLAC-2024-GRP",
+			want: ReleaseInfo{Title: "Artist Album (Deluxe_Edition)", Year: 2024, Source: "WEB", Audio: []string{"FLAC"}, Group: "GRP"},
+		},
+		{
+			name: "Artist - 1999 - Album",
+			want: ReleaseInfo{Title: "Artist - Album", Year: 1999},
+		},
+		{
+			name: "Artist - 1999 - Album (Remastered) FLAC",
+			want: ReleaseInfo{Title: "Artist - Album (Remastered)", Year: 1999, Audio: []string{"FLAC"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseReleaseName(tt.name)
+			if pack := got.IsSeasonPack(); pack != tt.pack {
+				t.Errorf("IsSeasonPack() = %v, want %v", pack, tt.pack)
+			}
+			if id := got.episodeID(); tt.episodeID != "" && id != tt.episodeID {
+				t.Errorf("episodeID() = %q, want %q", id, tt.episodeID)
+			}
+
+			got.seasonToken, got.episodeWidth, got.seasonAt = "", 0, 0
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseReleaseName() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReleaseInfoSpecials(t *testing.T) {
+	tests := []struct {
+		name    string
+		special bool
+		multi   bool
+	}{
+		{"Show.Name.S00E05.1080p.WEB.h264-GRP", true, false},
+		{"Show.Name.S01.SP01.1080p.WEB.h264-GRP", true, false},
+		{"[Group] Show Name - OVA2 (BD 1080p)", true, false},
+		{"Show.Name.S01.SPECIAL.1080p.WEB.h264-GRP", false, false},
+		{"Show.Name.S01E05.1080p.WEB.h264-GRP", false, false},
+		{"Show.Name.S01-S05.COMPLETE.1080p.BluRay.x264-GRP", false, true},
+		{"Show.Name.Complete.Series.1080p.WEB-DL.DDP5.1.H.264-GRP", false, true},
+		{"Show.Name.S01.1080p.BluRay.x264-GRP", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := parseReleaseName(tt.name)
+			if special := info.IsSpecial(); special != tt.special {
+				t.Errorf("IsSpecial() = %v, want %v", special, tt.special)
+			}
+			if multi := info.IsMultiSeason(); multi != tt.multi {
+				t.Errorf("IsMultiSeason() = %v, want %v", multi, tt.multi)
+			}
+		})
+	}
+}
+
+func TestNormalizeDate(t *testing.T) {
+	tests := []struct {
+		first, month, last string
+		want               string
+	}{
+		{"2024", "05", "17", "2024-05-17"},
+		{"17", "05", "2024", "2024-05-17"},
+		{"2024", "02", "30", ""},
+		{"31", "02", "2024", ""},
+		{"1850", "05", "17", ""},
+		{"2024", "5", "17", ""},
+		{"24", "05", "17", ""},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeDate(tt.first, tt.month, tt.last); got != tt.want {
+			t.Errorf("normalizeDate(%q, %q, %q) = %q, want %q", tt.first, tt.month, tt.last, got, tt.want)
+		}
+	}
+}
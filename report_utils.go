@@ -0,0 +1,148 @@
+package main
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// RunReport is the audit trail of a single run, written to archive/<release>/report.json
+type RunReport struct {
+	mu              sync.Mutex
+	Version         string              `json:"version"`
+	StartedAt       time.Time           `json:"started_at"`
+	FinishedAt      time.Time           `json:"finished_at"`
+	DryRun          bool                `json:"dry_run"`
+	QBittorrentArgs QBittorrentArgs     `json:"qbittorrent_args"`
+	ReleaseName     string              `json:"release_name"`
+	NameCheck       *ReleaseNameCheck   `json:"name_check,omitempty"`
+	SeasonPack      bool                `json:"season_pack"`
+	Verification    *VerificationResult `json:"verification,omitempty"`
+	Releases        []*ReleaseReport    `json:"releases"`
+	PostProcessing  []PostProcessReport `json:"post_processing"`
+}
+
+// ReleaseReport describes what was determined and uploaded for one release (or one episode of a season pack)
+type ReleaseReport struct {
+	ReleaseName    string             `json:"release_name"`
+	Info           ReleaseInfo        `json:"release_info"`
+	Category       CategoryResolution `json:"category"`
+	MediaFile      string             `json:"media_file,omitempty"`
+	Hash           string             `json:"hash,omitempty"`
+	Digests        FileDigests        `json:"digests,omitempty"`
+	HashSkipped    bool               `json:"hash_skipped"`
+	HashSkipReason string             `json:"hash_skip_reason,omitempty"`
+	NFOFile        string             `json:"nfo_file,omitempty"`
+	FileList       []FileListEntry    `json:"file_list"`
+	Uploads        []UploadReport     `json:"uploads"`
+}
+
+// UploadReport is the outcome of a single upload request
+type UploadReport struct {
+	Target     string `json:"target,omitempty"` // Uploader name, empty for CrowdNFO
+	Type       string `json:"type"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Response   string `json:"response,omitempty"`
+	Error      string `json:"error,omitempty"`
+	Queued     bool   `json:"queued,omitempty"`
+	Skipped    string `json:"skipped,omitempty"` // "history" or "duplicate"
+}
+
+// PostProcessReport is the outcome of a post-processing command
+type PostProcessReport struct {
+	Name      string   `json:"name"`
+	Command   string   `json:"command"`
+	Arguments []string `json:"arguments"`
+	ExitCode  int      `json:"exit_code"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// newRunReport starts the report for a torrent
+func newRunReport(config *Config, qbtArgs QBittorrentArgs) *RunReport {
+	return &RunReport{
+		Version:         Version,
+		StartedAt:       time.Now(),
+		DryRun:          config.DryRun,
+		QBittorrentArgs: qbtArgs,
+		ReleaseName:     qbtArgs.TorrentName,
+		Releases:        make([]*ReleaseReport, 0),
+		PostProcessing:  make([]PostProcessReport, 0),
+	}
+}
+
+// newRelease adds a release to the report, a nil report returns a detached release report
+func (r *RunReport) newRelease(releaseName string) *ReleaseReport {
+	release := &ReleaseReport{
+		ReleaseName: releaseName,
+		Info:        parseReleaseName(releaseName),
+		FileList:    make([]FileListEntry, 0),
+		Uploads:     make([]UploadReport, 0),
+	}
+	if r == nil {
+		return release
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Releases = append(r.Releases, release)
+	return release
+}
+
+// addPostProcessing records the result of a post-processing command
+func (r *RunReport) addPostProcessing(result PostProcessReport) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.PostProcessing = append(r.PostProcessing, result)
+}
+
+// write saves the report as report.json in the archive directory
+func (r *RunReport) write(log *Logger, archiveDir string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.FinishedAt = time.Now()
+	if err := writeJSONFile(filepath.Join(archiveDir, "report.json"), r); err != nil {
+		log.Warnf("⚠️ Failed to write processing report: %v", err)
+	}
+}
+
+// addUpload records the result of an upload request
+func (r *ReleaseReport) addUpload(target, uploadType string, result UploadResult, err error, queued bool) {
+	if r == nil {
+		return
+	}
+
+	upload := UploadReport{
+		Target:     target,
+		Type:       uploadType,
+		StatusCode: result.StatusCode,
+		Response:   result.Body,
+		Queued:     queued,
+	}
+	if err != nil {
+		upload.Error = err.Error()
+		if uploadErr, ok := err.(*UploadError); ok {
+			upload.StatusCode = uploadErr.StatusCode
+		}
+	}
+	r.Uploads = append(r.Uploads, upload)
+}
+
+// addSkippedUpload records an upload that was not needed because the target already has the file
+func (r *ReleaseReport) addSkippedUpload(target, uploadType, reason string, result UploadResult) {
+	if r == nil {
+		return
+	}
+
+	r.Uploads = append(r.Uploads, UploadReport{
+		Target:     target,
+		Type:       uploadType,
+		StatusCode: result.StatusCode,
+		Response:   result.Body,
+		Skipped:    reason,
+	})
+}
@@ -0,0 +1,358 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Uploader types
+const (
+	uploaderCrowdNFO  = "crowdnfo"
+	uploaderDirectory = "directory"
+	uploaderWebhook   = "webhook"
+)
+
+// Uploader sends NFOs, MediaInfo and file lists of a release to one target
+type Uploader interface {
+	Name() string // "crowdnfo" or the configured name, used in logs, history and queue
+	UploadFile(log *Logger, file FileUpload) (UploadResult, error)
+	UploadFileList(log *Logger, fileList FileListRequest) (UploadResult, error)
+	IsDuplicate(err error) bool // Reports whether an upload error means the target already has the upload
+}
+
+// FileUpload is an NFO or MediaInfo of a release
+type FileUpload struct {
+	ReleaseName      string
+	FileType         string // "NFO" or "MediaInfo"
+	OriginalFileName string
+	Data             []byte
+	Hash             string
+	Category         string
+	ArchiveDir       string
+}
+
+// UploaderConfig configures an upload target, all configured targets receive every upload
+type UploaderConfig struct {
+	Type    string            `json:"type"`              // "crowdnfo", "directory" or "webhook"
+	Name    string            `json:"name,omitempty"`    // Used in logs, history and queue, defaults to the type
+	Path    string            `json:"path,omitempty"`    // directory: target directory
+	URL     string            `json:"url,omitempty"`     // webhook: endpoint receiving a JSON POST per upload
+	Headers map[string]string `json:"headers,omitempty"` // webhook: additional headers, e.g. Authorization
+}
+
+// name returns the configured name or the type
+func (u UploaderConfig) name() string {
+	if u.Name != "" {
+		return u.Name
+	}
+	return u.Type
+}
+
+// validateUploaders checks types, required settings and unique names
+func validateUploaders(uploaders []UploaderConfig) error {
+	names := make(map[string]bool)
+	for i, uploader := range uploaders {
+		switch uploader.Type {
+		case uploaderCrowdNFO:
+			if uploader.name() != uploaderCrowdNFO {
+				return fmt.Errorf("uploader %d: the crowdnfo uploader can't be renamed", i+1)
+			}
+		case uploaderDirectory:
+			if uploader.Path == "" {
+				return fmt.Errorf("uploader %d: directory uploader needs a path", i+1)
+			}
+		case uploaderWebhook:
+			parsed, err := url.Parse(uploader.URL)
+			if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+				return fmt.Errorf("uploader %d: webhook uploader needs an http(s) url", i+1)
+			}
+		default:
+			return fmt.Errorf("uploader %d: unknown type '%s' (valid: %s, %s, %s)", i+1, uploader.Type, uploaderCrowdNFO, uploaderDirectory, uploaderWebhook)
+		}
+
+		if uploader.Type != uploaderCrowdNFO && uploader.name() == uploaderCrowdNFO {
+			return fmt.Errorf("uploader %d: the name 'crowdnfo' is reserved", i+1)
+		}
+		if names[uploader.name()] {
+			return fmt.Errorf("uploader %d: duplicate name '%s'", i+1, uploader.name())
+		}
+		names[uploader.name()] = true
+	}
+	return nil
+}
+
+// usesCrowdNFO reports whether uploads are sent to CrowdNFO, which is the case if no uploaders are configured
+func usesCrowdNFO(config *Config) bool {
+	if len(config.Uploaders) == 0 {
+		return true
+	}
+	for _, uploader := range config.Uploaders {
+		if uploader.Type == uploaderCrowdNFO {
+			return true
+		}
+	}
+	return false
+}
+
+// newUploaders creates the configured upload targets, CrowdNFO only if none are configured
+func newUploaders(config *Config) []Uploader {
+	if len(config.Uploaders) == 0 {
+		return []Uploader{&crowdNFOUploader{config: config}}
+	}
+
+	uploaders := make([]Uploader, 0, len(config.Uploaders))
+	for _, uploader := range config.Uploaders {
+		switch uploader.Type {
+		case uploaderCrowdNFO:
+			uploaders = append(uploaders, &crowdNFOUploader{config: config})
+		case uploaderDirectory:
+			uploaders = append(uploaders, &directoryUploader{name: uploader.name(), path: uploader.Path, config: config})
+		case uploaderWebhook:
+			uploaders = append(uploaders, &webhookUploader{name: uploader.name(), url: uploader.URL, headers: uploader.Headers, config: config})
+		}
+	}
+	return uploaders
+}
+
+// findUploader returns the upload target with the given history/queue target name, nil if it is no longer configured
+func findUploader(config *Config, target string) Uploader {
+	for _, uploader := range newUploaders(config) {
+		if uploaderTarget(uploader) == target {
+			return uploader
+		}
+	}
+	return nil
+}
+
+// uploaderTarget identifies an uploader in history, queue and report, empty for CrowdNFO to keep existing entries valid
+func uploaderTarget(uploader Uploader) string {
+	if uploader.Name() == uploaderCrowdNFO {
+		return ""
+	}
+	return uploader.Name()
+}
+
+// crowdNFOUploader sends uploads to the CrowdNFO API
+type crowdNFOUploader struct {
+	config *Config
+}
+
+func (u *crowdNFOUploader) Name() string {
+	return uploaderCrowdNFO
+}
+
+func (u *crowdNFOUploader) UploadFile(log *Logger, file FileUpload) (UploadResult, error) {
+	return uploadFile(log, u.config, file.ReleaseName, file.FileType, file.OriginalFileName, file.Data, file.Hash, file.Category, file.ArchiveDir)
+}
+
+func (u *crowdNFOUploader) UploadFileList(log *Logger, fileList FileListRequest) (UploadResult, error) {
+	return uploadFileList(log, u.config, fileList)
+}
+
+// IsDuplicate reports whether CrowdNFO rejected an upload because it was already submitted
+func (u *crowdNFOUploader) IsDuplicate(err error) bool {
+	uploadErr, ok := err.(*UploadError)
+	if !ok {
+		return false
+	}
+	return strings.Contains(strings.ToLower(uploadErr.Message), "already submitted")
+}
+
+// directoryUploader writes uploads to <path>/<release>/ and appends a line per upload to <path>/uploads.jsonl
+type directoryUploader struct {
+	name   string
+	path   string
+	config *Config
+}
+
+// DirectoryIndexEntry is a line of the uploads.jsonl index of a directory uploader
+type DirectoryIndexEntry struct {
+	Time        time.Time `json:"time"`
+	ReleaseName string    `json:"release_name"`
+	Category    string    `json:"category,omitempty"`
+	FileType    string    `json:"file_type"`
+	File        string    `json:"file"` // Relative to the uploader directory
+	Hash        string    `json:"hash,omitempty"`
+	Size        int       `json:"size"`
+}
+
+func (u *directoryUploader) Name() string {
+	return u.name
+}
+
+func (u *directoryUploader) UploadFile(log *Logger, file FileUpload) (UploadResult, error) {
+	fileName := file.OriginalFileName
+	if file.FileType != "NFO" || fileName == "" {
+		fileName = file.ReleaseName + "." + strings.ToLower(file.FileType) + ".json"
+	}
+	return u.write(log, file.ReleaseName, file.Category, file.FileType, fileName, file.Hash, file.Data)
+}
+
+func (u *directoryUploader) UploadFileList(log *Logger, fileList FileListRequest) (UploadResult, error) {
+	data, err := json.MarshalIndent(fileList, "", "  ")
+	if err != nil {
+		return UploadResult{}, fmt.Errorf("failed to marshal file list: %v", err)
+	}
+	return u.write(log, fileList.ReleaseName, fileList.Category, "FileList", fileList.ReleaseName+".filelist.json", "", data)
+}
+
+// IsDuplicate is always false, existing files are overwritten
+func (u *directoryUploader) IsDuplicate(err error) bool {
+	return false
+}
+
+// write stores the file and appends it to the index, write errors are reported as retryable upload errors
+func (u *directoryUploader) write(log *Logger, releaseName, category, fileType, fileName, hash string, data []byte) (UploadResult, error) {
+	relPath := filepath.Join(safePathName(releaseName), safePathName(fileName))
+	target := filepath.Join(u.path, relPath)
+
+	if u.config.DryRun {
+		log.Infof("🧪 DRY RUN: Would write %s (%d bytes) to %s", fileType, len(data), target)
+		return UploadResult{}, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return UploadResult{}, &UploadError{Message: fmt.Sprintf("failed to create directory: %v", err)}
+	}
+	if err := os.WriteFile(target, data, 0644); err != nil {
+		return UploadResult{}, &UploadError{Message: fmt.Sprintf("failed to write %s: %v", fileName, err)}
+	}
+
+	entry, err := json.Marshal(DirectoryIndexEntry{
+		Time:        time.Now(),
+		ReleaseName: releaseName,
+		Category:    category,
+		FileType:    fileType,
+		File:        filepath.ToSlash(relPath),
+		Hash:        hash,
+		Size:        len(data),
+	})
+	if err != nil {
+		return UploadResult{}, err
+	}
+
+	// Episodes are uploaded in parallel, other processes may write to the same index
+	indexPath := filepath.Join(u.path, "uploads.jsonl")
+	err = withFileLock(indexPath, func() error {
+		index, err := os.OpenFile(indexPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		defer index.Close()
+		_, err = index.Write(append(entry, '\n'))
+		return err
+	})
+	if err != nil {
+		return UploadResult{}, &UploadError{Message: fmt.Sprintf("failed to update index: %v", err)}
+	}
+
+	return UploadResult{}, nil
+}
+
+// safePathName keeps release and file names from escaping the uploader directory
+func safePathName(name string) string {
+	name = strings.NewReplacer("/", "_", "\\", "_").Replace(name)
+	if name == "." || name == ".." {
+		return "_"
+	}
+	return name
+}
+
+// webhookUploader posts every upload as JSON to a URL
+type webhookUploader struct {
+	name    string
+	url     string
+	headers map[string]string
+	config  *Config
+}
+
+// WebhookPayload is the JSON body sent by the webhook uploader
+type WebhookPayload struct {
+	ReleaseName      string          `json:"release_name"`
+	Category         string          `json:"category,omitempty"`
+	FileType         string          `json:"file_type"` // "NFO", "MediaInfo" or "FileList"
+	OriginalFileName string          `json:"original_file_name,omitempty"`
+	Hash             string          `json:"hash,omitempty"`
+	Data             []byte          `json:"data,omitempty"` // NFO or MediaInfo, base64 encoded
+	Entries          []FileListEntry `json:"entries,omitempty"`
+}
+
+func (u *webhookUploader) Name() string {
+	return u.name
+}
+
+func (u *webhookUploader) UploadFile(log *Logger, file FileUpload) (UploadResult, error) {
+	return u.post(log, WebhookPayload{
+		ReleaseName:      file.ReleaseName,
+		Category:         file.Category,
+		FileType:         file.FileType,
+		OriginalFileName: file.OriginalFileName,
+		Hash:             file.Hash,
+		Data:             file.Data,
+	})
+}
+
+func (u *webhookUploader) UploadFileList(log *Logger, fileList FileListRequest) (UploadResult, error) {
+	return u.post(log, WebhookPayload{
+		ReleaseName: fileList.ReleaseName,
+		Category:    fileList.Category,
+		FileType:    "FileList",
+		Entries:     fileList.Entries,
+	})
+}
+
+// IsDuplicate reports whether the webhook answered 409 Conflict
+func (u *webhookUploader) IsDuplicate(err error) bool {
+	uploadErr, ok := err.(*UploadError)
+	return ok && uploadErr.StatusCode == http.StatusConflict
+}
+
+func (u *webhookUploader) post(log *Logger, payload WebhookPayload) (UploadResult, error) {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return UploadResult{}, fmt.Errorf("failed to marshal webhook payload: %v", err)
+	}
+
+	req, err := http.NewRequest("POST", u.url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return UploadResult{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", getUserAgent())
+	for name, value := range u.headers {
+		req.Header.Set(name, value)
+	}
+
+	if u.config.DryRun {
+		// Configured headers usually carry credentials, none of them are printed in clear text
+		secretHeaders := make([]string, 0, len(u.headers))
+		for name := range u.headers {
+			secretHeaders = append(secretHeaders, name)
+		}
+		printDryRunRequest(log, req, secretHeaders...)
+		log.Infof("   Webhook payload: %s for %s (%d bytes)", payload.FileType, payload.ReleaseName, len(jsonData))
+		return UploadResult{}, nil
+	}
+
+	client := createHTTPClient(u.config, 30*time.Second)
+	resp, err := client.Do(req)
+	if err != nil {
+		return UploadResult{}, &UploadError{Message: err.Error()}
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	result := UploadResult{StatusCode: resp.StatusCode, Body: string(body)}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return result, &UploadError{StatusCode: resp.StatusCode, Message: fmt.Sprintf("webhook failed with status %d: %s", resp.StatusCode, string(body))}
+	}
+	return result, nil
+}
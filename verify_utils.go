@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Checksum file extensions and the algorithm they use
+var checksumFileAlgorithms = map[string]string{
+	".sfv":    "crc32",
+	".md5":    "md5",
+	".sha1":   "sha1",
+	".sha256": "sha256",
+}
+
+// ChecksumEntry is one line of a checksum file
+type ChecksumEntry struct {
+	ChecksumFile string `json:"checksum_file"`
+	File         string `json:"file"`
+	Algorithm    string `json:"algorithm"`
+	Expected     string `json:"expected"`
+	Actual       string `json:"actual,omitempty"`
+	Status       string `json:"status"` // ok, mismatch, missing, skipped or error
+	Error        string `json:"error,omitempty"`
+}
+
+// VerificationResult is the outcome of verifying a release against its checksum files
+type VerificationResult struct {
+	ChecksumFiles []string        `json:"checksum_files"`
+	Entries       []ChecksumEntry `json:"entries"`
+	Passed        bool            `json:"passed"`
+}
+
+var (
+	sfvLinePattern      = regexp.MustCompile(`^(.+?)\s+([0-9A-Fa-f]{8})$`)
+	checksumLinePattern = regexp.MustCompile(`^([0-9A-Fa-f]+)\s+\*?(.+)$`)
+)
+
+// findChecksumFiles returns all .sfv/.md5/.sha1/.sha256 files within the release directory
+func findChecksumFiles(releaseDir string) []string {
+	var checksumFiles []string
+	filepath.Walk(releaseDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		if _, ok := checksumFileAlgorithms[strings.ToLower(filepath.Ext(path))]; ok {
+			checksumFiles = append(checksumFiles, path)
+		}
+		return nil
+	})
+	return checksumFiles
+}
+
+// parseChecksumFile reads the entries of a checksum file, paths are resolved relative to the checksum file
+func parseChecksumFile(checksumFile string) ([]ChecksumEntry, error) {
+	algorithm := checksumFileAlgorithms[strings.ToLower(filepath.Ext(checksumFile))]
+
+	file, err := os.Open(checksumFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []ChecksumEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		if line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// SFV lists "filename CRC32", md5sum/sha1sum/sha256sum list "digest  filename" or "digest *filename"
+		var name, expected string
+		if algorithm == "crc32" {
+			match := sfvLinePattern.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+			name, expected = match[1], match[2]
+		} else {
+			match := checksumLinePattern.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+			expected, name = match[1], match[2]
+		}
+
+		// Checksum files created on Windows use backslashes
+		name = filepath.FromSlash(strings.ReplaceAll(name, "\\", "/"))
+		entries = append(entries, ChecksumEntry{
+			ChecksumFile: checksumFile,
+			File:         filepath.Join(filepath.Dir(checksumFile), name),
+			Algorithm:    algorithm,
+			Expected:     strings.ToLower(expected),
+		})
+	}
+
+	return entries, scanner.Err()
+}
+
+// resolveChecksumPath finds the referenced file, falling back to a case-insensitive match within its directory
+func resolveChecksumPath(path string) (string, bool) {
+	if _, err := os.Stat(path); err == nil {
+		return path, true
+	}
+
+	dirEntries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		return path, false
+	}
+	for _, entry := range dirEntries {
+		if strings.EqualFold(entry.Name(), filepath.Base(path)) {
+			return filepath.Join(filepath.Dir(path), entry.Name()), true
+		}
+	}
+	return path, false
+}
+
+// verifyChecksums verifies all files listed in checksum files of the release
+// Returns nil if verification is disabled or the release contains no checksum files
+func verifyChecksums(ctx context.Context, log *Logger, config *Config, releaseDir string) *VerificationResult {
+	if !config.Verification.Enabled {
+		return nil
+	}
+	if info, err := os.Stat(releaseDir); err != nil || !info.IsDir() {
+		return nil
+	}
+
+	checksumFiles := findChecksumFiles(releaseDir)
+	if len(checksumFiles) == 0 {
+		return nil
+	}
+
+	result := &VerificationResult{ChecksumFiles: checksumFiles, Entries: make([]ChecksumEntry, 0), Passed: true}
+	for _, checksumFile := range checksumFiles {
+		entries, err := parseChecksumFile(checksumFile)
+		if err != nil {
+			log.Warnf("⚠️ Failed to read checksum file %s: %v", filepath.Base(checksumFile), err)
+			continue
+		}
+		log.Infof("🔎 Verifying %d files against %s", len(entries), filepath.Base(checksumFile))
+
+		for _, entry := range entries {
+			if ctx.Err() != nil {
+				return result
+			}
+
+			path, found := resolveChecksumPath(entry.File)
+			entry.File = path
+			if !found {
+				entry.Status = "missing"
+			} else if shouldHash, err := shouldCalculateHash(log, config, path); err != nil {
+				entry.Status = "error"
+				entry.Error = err.Error()
+			} else if !shouldHash {
+				// Files above max_hash_file_size are not read at all, they can't be verified but didn't fail either
+				entry.Status = "skipped"
+				entry.Error = fmt.Sprintf("max_hash_file_size (%s)", config.MaxHashFileSize)
+			} else {
+				digests, err := cachedHashFile(ctx, log, config, path, []string{entry.Algorithm})
+				if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+					entry.Status = "skipped"
+					entry.Error = "hash calculation timed out"
+				} else if err != nil {
+					entry.Status = "error"
+					entry.Error = err.Error()
+				} else if entry.Actual = digests[entry.Algorithm]; entry.Actual == entry.Expected {
+					entry.Status = "ok"
+				} else {
+					entry.Status = "mismatch"
+				}
+			}
+
+			switch entry.Status {
+			case "mismatch":
+				log.Warnf("⚠️ Checksum mismatch: %s (expected %s, got %s)", filepath.Base(entry.File), entry.Expected, entry.Actual)
+			case "missing":
+				log.Warnf("⚠️ File listed in %s is missing: %s", filepath.Base(entry.ChecksumFile), filepath.Base(entry.File))
+			case "skipped":
+				log.Infof("⏭️ Not verifying %s: %s", filepath.Base(entry.File), entry.Error)
+			case "error":
+				log.Warnf("⚠️ Failed to verify %s: %s", filepath.Base(entry.File), entry.Error)
+			}
+			if entry.Status != "ok" && entry.Status != "skipped" {
+				result.Passed = false
+			}
+			result.Entries = append(result.Entries, entry)
+		}
+	}
+
+	if result.Passed {
+		log.Infof("✅ Checksum verification passed (%d files)", len(result.Entries))
+	} else {
+		log.Errorf("❌ Checksum verification failed")
+	}
+	return result
+}
+
+// fileFailed reports whether the given file failed verification
+func (v *VerificationResult) fileFailed(path string) bool {
+	if v == nil {
+		return false
+	}
+	for _, entry := range v.Entries {
+		if entry.Status != "ok" && entry.Status != "skipped" && filepath.Clean(entry.File) == filepath.Clean(path) {
+			return true
+		}
+	}
+	return false
+}